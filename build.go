@@ -4,18 +4,30 @@ import (
 	"strconv"
 
 	"github.com/denormal/go-gitconfig"
+	"github.com/denormal/go-gitinfo/ci"
 )
 
 const (
-	BRANCH     = "branch"
-	COMMIT     = "commit"
-	EDITOR     = "editor"
-	GIT        = "git"
-	MODIFIED   = "modified"
-	PATH       = "path"
-	ROOT       = "root"
-	USER_NAME  = "user.name"
-	USER_EMAIL = "user.email"
+	BRANCH          = "branch"
+	CI_BRANCH       = "ci.branch"
+	CI_PIPELINE     = "ci.pipeline"
+	CI_PROVIDER     = "ci.provider"
+	CI_PULL_REQUEST = "ci.pr"
+	CI_URL          = "ci.url"
+	COMMIT          = "commit"
+	COMMIT_SIGNED   = "commit.signed"
+	COUNT           = "count"
+	COUNT_OPTIONS   = "count.options"
+	EDITOR          = "editor"
+	GIT             = "git"
+	MODIFIED        = "modified"
+	MODIFIED_HASH   = "modified.hash"
+	MODIFIED_PATCH  = "modified.patch"
+	PATH            = "path"
+	ROOT            = "root"
+	URL_COMMIT      = "url.commit"
+	USER_NAME       = "user.name"
+	USER_EMAIL      = "user.email"
 )
 
 func Build(kv map[string]string) GitInfo {
@@ -25,16 +37,43 @@ func Build(kv map[string]string) GitInfo {
 		_modified = true
 	}
 
+	// the build information only records whether the commit was validly
+	// signed, not the details of the signature
+	_commit := newCommit(nil, kv[COMMIT])
+	if kv[COMMIT_SIGNED] == "true" {
+		_commit.signed.Do(func() {
+			_commit.signature = &signature{status: SIGNATURE_GOOD}
+		})
+	}
+
+	// was the build information captured under CI?
+	var _ci ci.CI
+	if kv[CI_PROVIDER] != "" {
+		_ci = ci.New(
+			kv[CI_PROVIDER],
+			kv[CI_BRANCH],
+			kv[CI_PULL_REQUEST],
+			kv[CI_PIPELINE],
+			kv[CI_URL],
+		)
+	}
+
 	// return the GitInfo structure
 	return &build{
 		gitinfo:  gitinfo{},
 		branch:   kv[BRANCH],
-		commit:   &commit{kv[COMMIT]},
+		ci:       _ci,
+		commit:   _commit,
+		count:    kv[COUNT],
+		counted:  ParseCountOptions(kv[COUNT_OPTIONS]),
 		editor:   kv[EDITOR],
 		git:      kv[GIT],
+		hash:     kv[MODIFIED_HASH],
 		modified: _modified,
+		patch:    kv[MODIFIED_PATCH],
 		path:     kv[PATH],
 		root:     kv[ROOT],
+		url:      kv[URL_COMMIT],
 		user:     &user{kv[USER_NAME], kv[USER_EMAIL]},
 	}
 } // Build()
@@ -43,37 +82,128 @@ type build struct {
 	gitinfo
 
 	branch   string
+	ci       ci.CI
 	commit   Commit
+	count    string
+	counted  CountOptions
 	editor   string
 	git      string
+	hash     string
 	modified bool
+	patch    string
 	path     string
 	root     string
+	url      string
 	user     User
 }
 
 func (b build) Branch() (string, error)     { return b.branch, nil }
+func (b build) CI() ci.CI                   { return b.ci }
 func (b build) Commit() (Commit, error)     { return b.commit, nil }
 func (b build) Config() gitconfig.GitConfig { return nil }
 func (b build) Path() string                { return b.path }
 func (b build) Root() string                { return b.root }
+func (b build) DirtyHash() (string, error)  { return b.hash, nil }
 func (b build) Editor() string              { return b.editor }
 func (b build) Modified() (bool, error)     { return b.modified, nil }
 func (b build) User() User                  { return b.user }
 func (b build) Git() (string, error)        { return b.git, nil }
 
+// ModifiedWithOptions returns the modified state captured by the build
+// information, since the modifications themselves are not captured.
+func (b build) ModifiedWithOptions(opts ModifiedOptions) (bool, error) {
+	return b.modified, nil
+} // ModifiedWithOptions()
+
+// Changelog returns the MissingWorkingCopyError, since the commit history
+// is not captured by the build information.
+func (b build) Changelog(from, to string) (Changelog, error) {
+	return nil, MissingWorkingCopyError
+} // Changelog()
+
+// CommitCount returns the commit count captured by the build information,
+// which is only available for the options used to count the commits.
+// Otherwise, CommitCount returns the MissingWorkingCopyError.
+func (b build) CommitCount(opts CountOptions) (int, error) {
+	if opts != b.counted || b.count == "" {
+		return 0, MissingWorkingCopyError
+	}
+
+	return strconv.Atoi(b.count)
+} // CommitCount()
+
+// Commits returns the MissingWorkingCopyError, since the commit history
+// is not captured by the build information.
+func (b build) Commits(from, to string) ([]Commit, error) {
+	return nil, MissingWorkingCopyError
+} // Commits()
+
+// IsShallow returns the MissingWorkingCopyError, since the clone details
+// are not captured by the build information.
+func (b build) IsShallow() (bool, error) {
+	return false, MissingWorkingCopyError
+} // IsShallow()
+
+// PartialCloneFilter returns the MissingWorkingCopyError, since the clone
+// details are not captured by the build information.
+func (b build) PartialCloneFilter() (string, error) {
+	return "", MissingWorkingCopyError
+} // PartialCloneFilter()
+
+// Shallow returns the MissingWorkingCopyError, since the clone details are
+// not captured by the build information.
+func (b build) Shallow() ([]Commit, error) {
+	return nil, MissingWorkingCopyError
+} // Shallow()
+
+// Patch returns the Patch captured by the build information, if the build
+// information was generated with the patch of the working copy. Otherwise,
+// Patch returns the MissingPatchError.
+func (b build) Patch() (Patch, error) {
+	if b.patch == "" {
+		return nil, MissingPatchError
+	}
+
+	return ParsePatch(b.patch)
+} // Patch()
+
+// PseudoVersion returns the empty string, since the commit time is not
+// captured by the build information.
+func (b build) PseudoVersion() (string, error) { return "", nil }
+
 func (b build) Map() map[string]string {
-	return map[string]string{
-		BRANCH:     b.branch,
-		COMMIT:     b.commit.String(),
-		EDITOR:     b.editor,
-		GIT:        b.git,
-		MODIFIED:   strconv.FormatBool(b.modified),
-		PATH:       b.path,
-		ROOT:       b.root,
-		USER_EMAIL: b.user.Email(),
-		USER_NAME:  b.user.Name(),
+	_signature, _ := b.commit.Signature()
+
+	_map := map[string]string{
+		BRANCH:        b.branch,
+		COMMIT:        b.commit.String(),
+		COMMIT_SIGNED: strconv.FormatBool(_signature.Valid()),
+		COUNT:         b.count,
+		EDITOR:        b.editor,
+		GIT:           b.git,
+		MODIFIED:      strconv.FormatBool(b.modified),
+		MODIFIED_HASH: b.hash,
+		PATH:          b.path,
+		ROOT:          b.root,
+		URL_COMMIT:    b.url,
+		USER_EMAIL:    b.user.Email(),
+		USER_NAME:     b.user.Name(),
 	}
+	for _k, _v := range cimap(b.ci) {
+		_map[_k] = _v
+	}
+
+	// the patch is only included if it was captured
+	if b.patch != "" {
+		_map[MODIFIED_PATCH] = b.patch
+	}
+
+	// the count options are only included if they are not the defaults
+	if b.counted != (CountOptions{}) {
+		_map[COUNT_OPTIONS] = b.counted.String()
+	}
+
+	return _map
 } // Map()
 
 // ensure the static type implements the GitInfo interface
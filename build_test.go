@@ -1,6 +1,7 @@
 package gitinfo_test
 
 import (
+	"strconv"
 	"testing"
 
 	"github.com/denormal/go-gitinfo"
@@ -11,16 +12,25 @@ const _NONSENSE = "my.nonsense.field"
 func TestBuild(t *testing.T) {
 	// create a GitInfo instance
 	_map := map[string]string{
-		gitinfo.BRANCH:     "branch",
-		gitinfo.COMMIT:     "commit",
-		gitinfo.EDITOR:     "editor",
-		gitinfo.GIT:        "git",
-		gitinfo.MODIFIED:   "true",
-		gitinfo.PATH:       "path",
-		gitinfo.ROOT:       "root",
-		gitinfo.USER_NAME:  "user.name",
-		gitinfo.USER_EMAIL: "user.email",
-		_NONSENSE:          "nonsense",
+		gitinfo.BRANCH:          "branch",
+		gitinfo.CI_BRANCH:       "ci.branch",
+		gitinfo.CI_PIPELINE:     "ci.pipeline",
+		gitinfo.CI_PROVIDER:     "ci.provider",
+		gitinfo.CI_PULL_REQUEST: "ci.pr",
+		gitinfo.CI_URL:          "ci.url",
+		gitinfo.COMMIT:          "commit",
+		gitinfo.COMMIT_SIGNED:   "true",
+		gitinfo.COUNT:           "42",
+		gitinfo.EDITOR:          "editor",
+		gitinfo.GIT:             "git",
+		gitinfo.MODIFIED:        "true",
+		gitinfo.MODIFIED_HASH:   "modified.hash",
+		gitinfo.PATH:            "path",
+		gitinfo.ROOT:            "root",
+		gitinfo.URL_COMMIT:      "url.commit",
+		gitinfo.USER_NAME:       "user.name",
+		gitinfo.USER_EMAIL:      "user.email",
+		_NONSENSE:               "nonsense",
 	}
 
 	// ensure Build creates the requisite model
@@ -41,6 +51,17 @@ func TestBuild(t *testing.T) {
 			"unexpected Config(); expected %v, got %v", nil, _config,
 		)
 	}
+	//		- ci
+	_ci := _git.CI()
+	if _ci == nil {
+		t.Fatalf("unexpected nil from CI()")
+	} else if _ci.Provider() != _map[gitinfo.CI_PROVIDER] ||
+		_ci.Branch() != _map[gitinfo.CI_BRANCH] ||
+		_ci.PullRequest() != _map[gitinfo.CI_PULL_REQUEST] ||
+		_ci.Pipeline() != _map[gitinfo.CI_PIPELINE] ||
+		_ci.URL() != _map[gitinfo.CI_URL] {
+		t.Fatalf("unexpected CI(): %v", _ci)
+	}
 	//		- commit
 	_commit, _err := _git.Commit()
 	if _err != nil {
@@ -53,6 +74,33 @@ func TestBuild(t *testing.T) {
 			_map[gitinfo.COMMIT], _commit.String(),
 		)
 	}
+	//		- commit signature
+	_signature, _err := _commit.Signature()
+	if _err != nil {
+		t.Fatalf("unexpected error in Signature(): %s", _err.Error())
+	} else if !_signature.Valid() { // the test set above uses "true"
+		t.Fatalf(
+			"unexpected Signature(); expected valid, got %s",
+			_signature.Status(),
+		)
+	}
+	//		- commit count
+	_count, _err := _git.CommitCount(gitinfo.CountOptions{})
+	if _err != nil {
+		t.Fatalf("unexpected error in CommitCount(): %s", _err.Error())
+	} else if strconv.Itoa(_count) != _map[gitinfo.COUNT] {
+		t.Fatalf(
+			"unexpected CommitCount(); expected %s, got %d",
+			_map[gitinfo.COUNT], _count,
+		)
+	}
+	_, _err = _git.CommitCount(gitinfo.CountOptions{FirstParent: true})
+	if _err != gitinfo.MissingWorkingCopyError {
+		t.Fatalf(
+			"unexpected error in CommitCount(); expected %v, got %v",
+			gitinfo.MissingWorkingCopyError, _err,
+		)
+	}
 	//		- editor
 	_editor := _git.Editor()
 	if _editor != _map[gitinfo.EDITOR] {
@@ -71,6 +119,16 @@ func TestBuild(t *testing.T) {
 			_map[gitinfo.MODIFIED], _modified,
 		)
 	}
+	//		- dirty hash
+	_hash, _err := _git.DirtyHash()
+	if _err != nil {
+		t.Fatalf("unexpected error in DirtyHash(): %s", _err.Error())
+	} else if _hash != _map[gitinfo.MODIFIED_HASH] {
+		t.Fatalf(
+			"unexpected DirtyHash(); expected %q, got %q",
+			_map[gitinfo.MODIFIED_HASH], _hash,
+		)
+	}
 	//		- path
 	_path := _git.Path()
 	if _path != _map[gitinfo.PATH] {
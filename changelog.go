@@ -0,0 +1,355 @@
+package gitinfo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/denormal/go-gitinfo/semver"
+	"github.com/denormal/go-gittools"
+)
+
+// the length of the commit hash prefix used when rendering changelogs
+const _CHANGELOG_HASH = 7
+
+// the titles of the Conventional Commit types, in changelog order
+var _TYPES = []struct{ typ, title string }{
+	{"feat", "Features"},
+	{"fix", "Bug Fixes"},
+	{"perf", "Performance Improvements"},
+	{"revert", "Reverts"},
+	{"refactor", "Code Refactoring"},
+	{"docs", "Documentation"},
+	{"style", "Styles"},
+	{"test", "Tests"},
+	{"build", "Build System"},
+	{"ci", "Continuous Integration"},
+	{"chore", "Chores"},
+}
+
+// Change represents a single commit within a Changelog.
+type Change interface {
+	// Commit returns the commit for this change.
+	Commit() Commit
+
+	// Type returns the Conventional Commit type of the change, or the empty
+	// string if the commit message does not follow Conventional Commits.
+	Type() string
+
+	// Scope returns the scope of the change, or the empty string if the
+	// change has no scope.
+	Scope() string
+
+	// Description returns the description of the change.
+	Description() string
+
+	// Breaking returns true if this is a breaking change.
+	Breaking() bool
+
+	// Notes returns the breaking change notes for this change.
+	Notes() []string
+
+	// Trailers returns the trailers of the commit message, keyed by token,
+	// excluding the breaking change notes.
+	Trailers() map[string][]string
+}
+
+// Group represents the changes of a Changelog of a given type and scope.
+type Group struct {
+	Type    string   // the Conventional Commit type (or "" for other changes)
+	Title   string   // the title of the type (e.g. "Features")
+	Scope   string   // the scope of the changes (or "" for no scope)
+	Changes []Change // the changes of this type and scope
+}
+
+// Changelog represents the changes between two commits of a working copy.
+type Changelog interface {
+	// From returns the revision the changelog starts from (exclusive), or
+	// the empty string if the changelog includes all commits reachable from
+	// To().
+	From() string
+
+	// To returns the revision the changelog ends with (inclusive).
+	To() string
+
+	// Changes returns the changes of the changelog, most recent first.
+	Changes() []Change
+
+	// Breaking returns the breaking changes of the changelog.
+	Breaking() []Change
+
+	// Groups returns the changes grouped by type and scope, in changelog
+	// order, with changes within each group ordered most recent first.
+	Groups() []Group
+
+	// Markdown returns the changelog rendered as Markdown.
+	Markdown() string
+
+	// JSON returns the changelog rendered as JSON.
+	JSON() ([]byte, error)
+}
+
+// Changelog returns the Changelog for the commits reachable from to, that
+// are not reachable from from. If from is "", the changelog starts from the
+// most recent semantic version tag reachable from to, excluding tags of the
+// to commit itself. If to is "", the changelog ends with HEAD. Merge commits
+// are not included in the changelog. An error is returned if the GitInfo
+// instance was initialised for a path not within a working copy, or there is
+// a problem extracting the commit history. If the commits are truncated by
+// the boundary of a shallow clone, a ShallowCloneError is returned.
+func (g *gitinfo) Changelog(from, to string) (Changelog, error) {
+	_root := g.Root()
+	if _root == "" {
+		return nil, MissingWorkingCopyError
+	}
+	if to == "" {
+		to = "HEAD"
+	}
+
+	// if we don't have a starting point, use the previous version tag
+	if from == "" {
+		_to, _err := g.resolve(to)
+		if _err != nil {
+			return nil, _err
+		}
+		_output, _err := gittools.RunInPath(
+			_root, "tag", "--merged", _to, "--no-contains", _to,
+		)
+		if _err != nil {
+			return nil, _err
+		}
+		var _previous semver.Version
+		for _, _tag := range lines(_output) {
+			_version, _err := semver.Parse(_tag)
+			if _err != nil {
+				continue
+			} else if _previous == nil || _version.Compare(_previous) > 0 {
+				_previous = _version
+			}
+		}
+		if _previous != nil {
+			from = _previous.String()
+		}
+	}
+
+	// extract the commits in the range
+	_commits, _err := g.log(from, to, "--no-merges")
+	if _err != nil {
+		return nil, _err
+	}
+
+	// parse the commit messages
+	_changelog := &changelog{from: from, to: to, changes: make([]Change, 0)}
+	for _, _commit := range _commits {
+		_message, _ := _commit.Message()
+		_changelog.changes = append(_changelog.changes, &change{
+			commit:  _commit,
+			message: semver.ParseMessage(_message),
+		})
+	}
+
+	return _changelog, nil
+} // Changelog()
+
+type change struct {
+	commit  Commit
+	message semver.Message
+}
+
+func (c *change) Commit() Commit      { return c.commit }
+func (c *change) Type() string        { return c.message.Type() }
+func (c *change) Scope() string       { return c.message.Scope() }
+func (c *change) Description() string { return c.message.Description() }
+func (c *change) Breaking() bool      { return c.message.Breaking() }
+func (c *change) Notes() []string     { return c.message.Notes() }
+
+// Trailers returns the trailers of the commit message, keyed by token,
+// excluding the breaking change notes.
+func (c *change) Trailers() map[string][]string {
+	_trailers := c.commit.Trailers()
+	delete(_trailers, semver.BREAKING_CHANGE_HYPHEN)
+
+	return _trailers
+} // Trailers()
+
+// MarshalJSON returns the JSON representation of the change.
+func (c *change) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Commit      string              `json:"commit"`
+		Type        string              `json:"type,omitempty"`
+		Scope       string              `json:"scope,omitempty"`
+		Description string              `json:"description"`
+		Breaking    bool                `json:"breaking"`
+		Notes       []string            `json:"notes,omitempty"`
+		Trailers    map[string][]string `json:"trailers,omitempty"`
+	}{
+		c.commit.String(),
+		c.Type(),
+		c.Scope(),
+		c.Description(),
+		c.Breaking(),
+		c.Notes(),
+		c.Trailers(),
+	})
+} // MarshalJSON()
+
+type changelog struct {
+	from    string
+	to      string
+	changes []Change
+}
+
+func (c *changelog) From() string      { return c.from }
+func (c *changelog) To() string        { return c.to }
+func (c *changelog) Changes() []Change { return c.changes }
+
+// Breaking returns the breaking changes of the changelog.
+func (c *changelog) Breaking() []Change {
+	_breaking := make([]Change, 0)
+	for _, _change := range c.changes {
+		if _change.Breaking() {
+			_breaking = append(_breaking, _change)
+		}
+	}
+
+	return _breaking
+} // Breaking()
+
+// Groups returns the changes grouped by type and scope, in changelog
+// order, with changes within each group ordered most recent first.
+func (c *changelog) Groups() []Group {
+	// group the changes by type and scope
+	_groups := make(map[string]map[string][]Change)
+	for _, _change := range c.changes {
+		_type, _scope := _change.Type(), _change.Scope()
+		if _groups[_type] == nil {
+			_groups[_type] = make(map[string][]Change)
+		}
+		_groups[_type][_scope] = append(_groups[_type][_scope], _change)
+	}
+
+	// determine the order of the types
+	//		- known types are ordered by significance
+	//		- unknown types are ordered alphabetically
+	//		- changes that are not conventional are listed last
+	_titles := make(map[string]string)
+	_types := make([]string, 0, len(_groups))
+	for _, _t := range _TYPES {
+		_titles[_t.typ] = _t.title
+		if _groups[_t.typ] != nil {
+			_types = append(_types, _t.typ)
+		}
+	}
+	_unknown := make([]string, 0)
+	for _type, _ := range _groups {
+		if _, _ok := _titles[_type]; !_ok && _type != "" {
+			_unknown = append(_unknown, _type)
+			_titles[_type] = _type
+		}
+	}
+	sort.Strings(_unknown)
+	_types = append(_types, _unknown...)
+	if _groups[""] != nil {
+		_types = append(_types, "")
+		_titles[""] = "Other Changes"
+	}
+
+	// construct the groups
+	//		- changes without a scope precede scoped changes
+	_rtn := make([]Group, 0)
+	for _, _type := range _types {
+		_scopes := make([]string, 0, len(_groups[_type]))
+		for _scope, _ := range _groups[_type] {
+			_scopes = append(_scopes, _scope)
+		}
+		sort.Strings(_scopes)
+		for _, _scope := range _scopes {
+			_rtn = append(_rtn, Group{
+				Type:    _type,
+				Title:   _titles[_type],
+				Scope:   _scope,
+				Changes: _groups[_type][_scope],
+			})
+		}
+	}
+
+	return _rtn
+} // Groups()
+
+// Markdown returns the changelog rendered as Markdown.
+func (c *changelog) Markdown() string {
+	var _buffer bytes.Buffer
+
+	// the changelog heading describes the range of commits
+	if c.from == "" {
+		fmt.Fprintf(&_buffer, "## %s\n", c.to)
+	} else {
+		fmt.Fprintf(&_buffer, "## %s..%s\n", c.from, c.to)
+	}
+
+	// output the breaking changes
+	_breaking := c.Breaking()
+	if len(_breaking) != 0 {
+		fmt.Fprintf(&_buffer, "\n### BREAKING CHANGES\n\n")
+		for _, _change := range _breaking {
+			for _, _note := range _change.Notes() {
+				fmt.Fprintf(&_buffer, "* %s\n", item(_change, _note))
+			}
+		}
+	}
+
+	// output the changes by type and scope
+	_title := ""
+	for _, _group := range c.Groups() {
+		if _group.Title != _title {
+			fmt.Fprintf(&_buffer, "\n### %s\n\n", _group.Title)
+			_title = _group.Title
+		}
+		for _, _change := range _group.Changes {
+			fmt.Fprintf(
+				&_buffer, "* %s\n", item(_change, _change.Description()),
+			)
+		}
+	}
+
+	return _buffer.String()
+} // Markdown()
+
+// JSON returns the changelog rendered as JSON.
+func (c *changelog) JSON() ([]byte, error) {
+	type _group struct {
+		Type    string   `json:"type"`
+		Title   string   `json:"title"`
+		Scope   string   `json:"scope,omitempty"`
+		Changes []Change `json:"changes"`
+	}
+
+	_groups := make([]_group, 0)
+	for _, _g := range c.Groups() {
+		_groups = append(_groups, _group{_g.Type, _g.Title, _g.Scope, _g.Changes})
+	}
+
+	return json.MarshalIndent(struct {
+		From     string   `json:"from"`
+		To       string   `json:"to"`
+		Breaking []Change `json:"breaking"`
+		Groups   []_group `json:"groups"`
+	}{c.from, c.to, c.Breaking(), _groups}, "", "  ")
+} // JSON()
+
+// item returns the Markdown list item text for the given change and text,
+// including the change scope and the abbreviated commit hash
+func item(c Change, text string) string {
+	// indent continuation lines so they remain part of the list item
+	_text := strings.Replace(text, "\n", "\n  ", -1)
+	if c.Scope() != "" {
+		_text = fmt.Sprintf("**%s:** %s", c.Scope(), _text)
+	}
+
+	return fmt.Sprintf("%s (%s)", _text, c.Commit().Prefix(_CHANGELOG_HASH))
+} // item()
+
+// ensure changelog supports the Changelog interface
+var _ Changelog = &changelog{}
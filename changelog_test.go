@@ -0,0 +1,208 @@
+package gitinfo_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/denormal/go-gitinfo"
+	"github.com/denormal/go-gittools"
+)
+
+func TestChangelog(t *testing.T) {
+	// if we don't have git installed, then skip this test
+	if !gittools.HasGit() {
+		t.Skip("git not installed")
+	}
+
+	// create a repository with a release and subsequent changes
+	_dir := repository(t)
+	defer os.RemoveAll(_dir)
+
+	_when := "2024-01-01T00:00:00Z"
+	commit(t, _dir, _when, "feat: initial release", "v1.0.0")
+	commit(t, _dir, _when, "fix(cli): handle missing paths")
+	commit(t, _dir, _when, "docs: describe the changelog")
+	commit(t, _dir, _when, "feat(api): add Changelog()\n\nRefs: #28")
+	_breaking := commit(t, _dir, _when,
+		"feat(api)!: rename Version()\n\n"+
+			"BREAKING CHANGE: Version() is now Git()\n"+
+			"Signed-off-by: Jane Doe <jane@example.com>",
+	)
+	commit(t, _dir, _when, "fix: handle empty repositories")
+	commit(t, _dir, _when, "Update README")
+
+	_info, _err := gitinfo.NewWithPath(_dir)
+	if _err != nil {
+		t.Fatalf("unexpected error from NewWithPath(): %s", _err.Error())
+	}
+
+	// the changelog should default to the range since the last version tag
+	_changelog, _err := _info.Changelog("", "")
+	if _err != nil {
+		t.Fatalf("unexpected error from Changelog(): %s", _err.Error())
+	} else if _changelog.From() != "v1.0.0" {
+		t.Fatalf(
+			"unexpected from; expected %q, got %q",
+			"v1.0.0", _changelog.From(),
+		)
+	} else if _changelog.To() != "HEAD" {
+		t.Fatalf(
+			"unexpected to; expected %q, got %q", "HEAD", _changelog.To(),
+		)
+	} else if len(_changelog.Changes()) != 6 {
+		t.Fatalf(
+			"unexpected changes; expected %d, got %d",
+			6, len(_changelog.Changes()),
+		)
+	}
+
+	// ensure the changes are grouped by type and scope
+	_groups := make([]string, 0)
+	for _, _group := range _changelog.Groups() {
+		_groups = append(
+			_groups,
+			_group.Type+"("+_group.Scope+"):"+
+				strings.Repeat("*", len(_group.Changes)),
+		)
+	}
+	_expected := "feat(api):** fix():* fix(cli):* docs():* ():*"
+	if strings.Join(_groups, " ") != _expected {
+		t.Fatalf(
+			"unexpected groups; expected %q, got %q",
+			_expected, strings.Join(_groups, " "),
+		)
+	}
+
+	// ensure the breaking change and its trailers are reported
+	_changes := _changelog.Breaking()
+	if len(_changes) != 1 {
+		t.Fatalf(
+			"unexpected breaking changes; expected %d, got %d",
+			1, len(_changes),
+		)
+	}
+	_change := _changes[0]
+	if _change.Commit().String() != _breaking {
+		t.Fatalf(
+			"unexpected breaking commit; expected %q, got %q",
+			_breaking, _change.Commit().String(),
+		)
+	} else if _notes := _change.Notes(); len(_notes) != 1 ||
+		_notes[0] != "Version() is now Git()" {
+		t.Fatalf("unexpected breaking change notes: %q", _notes)
+	}
+	_trailers := _change.Trailers()
+	if len(_trailers) != 1 ||
+		len(_trailers["Signed-off-by"]) != 1 ||
+		_trailers["Signed-off-by"][0] != "Jane Doe <jane@example.com>" {
+		t.Fatalf("unexpected trailers: %v", _trailers)
+	}
+
+	// ensure the Markdown includes the breaking changes and each section
+	_markdown := _changelog.Markdown()
+	for _, _text := range []string{
+		"## v1.0.0..HEAD\n",
+		"### BREAKING CHANGES\n\n* **api:** Version() is now Git() (" +
+			_breaking[:7] + ")\n",
+		"### Features\n",
+		"### Bug Fixes\n",
+		"* **cli:** handle missing paths (",
+		"### Documentation\n",
+		"### Other Changes\n\n* Update README (",
+	} {
+		if !strings.Contains(_markdown, _text) {
+			t.Fatalf("expected %q in Markdown; got %q", _text, _markdown)
+		}
+	}
+
+	// ensure the JSON is well-formed
+	_bytes, _err := _changelog.JSON()
+	if _err != nil {
+		t.Fatalf("unexpected error from JSON(): %s", _err.Error())
+	}
+	var _json struct {
+		From     string
+		To       string
+		Breaking []struct {
+			Commit   string
+			Trailers map[string][]string
+		}
+		Groups []struct {
+			Type    string
+			Changes []interface{}
+		}
+	}
+	_err = json.Unmarshal(_bytes, &_json)
+	if _err != nil {
+		t.Fatalf("unexpected error parsing JSON: %s", _err.Error())
+	} else if _json.From != "v1.0.0" || _json.To != "HEAD" {
+		t.Fatalf("unexpected JSON range: %s..%s", _json.From, _json.To)
+	} else if len(_json.Breaking) != 1 ||
+		_json.Breaking[0].Commit != _breaking ||
+		len(_json.Breaking[0].Trailers["Signed-off-by"]) != 1 {
+		t.Fatalf("unexpected JSON breaking changes: %v", _json.Breaking)
+	} else if len(_json.Groups) != 5 {
+		t.Fatalf("unexpected JSON groups: %v", _json.Groups)
+	}
+
+	// ensure explicit ranges are honoured
+	_changelog, _err = _info.Changelog("HEAD~3", "HEAD~2")
+	if _err != nil {
+		t.Fatalf("unexpected error from Changelog(): %s", _err.Error())
+	} else if len(_changelog.Changes()) != 1 ||
+		_changelog.Changes()[0].Commit().String() != _breaking {
+		t.Fatalf("unexpected changes: %v", _changelog.Changes())
+	}
+
+	// a tagged commit starts from the previous tag
+	git(t, _dir, "tag", "v2.0.0")
+	_changelog, _err = _info.Changelog("", "v2.0.0")
+	if _err != nil {
+		t.Fatalf("unexpected error from Changelog(): %s", _err.Error())
+	} else if _changelog.From() != "v1.0.0" {
+		t.Fatalf(
+			"unexpected from; expected %q, got %q",
+			"v1.0.0", _changelog.From(),
+		)
+	}
+
+	// without a previous tag, all commits are included
+	_changelog, _err = _info.Changelog("", "v1.0.0")
+	if _err != nil {
+		t.Fatalf("unexpected error from Changelog(): %s", _err.Error())
+	} else if _changelog.From() != "" || len(_changelog.Changes()) != 1 {
+		t.Fatalf(
+			"unexpected changelog from %q with %d changes",
+			_changelog.From(), len(_changelog.Changes()),
+		)
+	}
+
+	// refs that resemble git options are refused
+	_output := filepath.Join(_dir, "output")
+	for _, _range := range [][2]string{
+		{"--output=" + _output, "HEAD"},
+		{"", "--output=" + _output},
+		{"v1.0.0", "missing"},
+	} {
+		_, _err = _info.Changelog(_range[0], _range[1])
+		if _, _ok := _err.(*gitinfo.InvalidRefError); !_ok {
+			t.Fatalf(
+				"expected InvalidRefError from Changelog(%q, %q); got %v",
+				_range[0], _range[1], _err,
+			)
+		}
+		_, _err = _info.Commits(_range[0], _range[1])
+		if _, _ok := _err.(*gitinfo.InvalidRefError); !_ok {
+			t.Fatalf(
+				"expected InvalidRefError from Commits(%q, %q); got %v",
+				_range[0], _range[1], _err,
+			)
+		}
+	}
+	if _, _err := os.Stat(_output); !os.IsNotExist(_err) {
+		t.Fatalf("unexpected output file %s", _output)
+	}
+} // TestChangelog()
@@ -0,0 +1,233 @@
+package ci
+
+import (
+	"os"
+	"path"
+	"strings"
+)
+
+// the supported CI providers
+const (
+	BUILDKITE = "buildkite"
+	CIRCLECI  = "circleci"
+	GITHUB    = "github"
+	GITLAB    = "gitlab"
+	JENKINS   = "jenkins"
+)
+
+// CI represents the continuous integration environment of a build.
+type CI interface {
+	// Provider returns the name of the CI provider; one of BUILDKITE,
+	// CIRCLECI, GITHUB, GITLAB or JENKINS.
+	Provider() string
+
+	// Branch returns the name of the branch being built. For pull request
+	// builds, this is the source branch of the pull request. Branch returns
+	// the empty string if the build is not of a branch (e.g. a tag build).
+	Branch() string
+
+	// PullRequest returns the pull (or merge) request number of the build,
+	// or the empty string if this is not a pull request build.
+	PullRequest() string
+
+	// Pipeline returns the identifier of the build pipeline.
+	Pipeline() string
+
+	// URL returns the web URL of the build, or the empty string if this is
+	// not known.
+	URL() string
+
+	// Commit returns the hash of the commit being built, or the empty
+	// string if this is not known.
+	Commit() string
+}
+
+type ci struct {
+	provider string
+	branch   string
+	pr       string
+	pipeline string
+	url      string
+	commit   string
+}
+
+// New returns the CI instance with the given details.
+func New(provider, branch, pr, pipeline, url string) CI {
+	return &ci{
+		provider: provider,
+		branch:   branch,
+		pr:       pr,
+		pipeline: pipeline,
+		url:      url,
+	}
+} // New()
+
+// Detect returns the CI environment of the current process, or nil if the
+// process is not running under a recognised CI provider.
+func Detect() CI {
+	return Parse(os.Environ())
+} // Detect()
+
+// Parse returns the CI environment described by the given environment
+// variables, in the "key=value" form returned by os.Environ(), or nil if the
+// environment is not of a recognised CI provider.
+func Parse(environ []string) CI {
+	_env := make(map[string]string)
+	for _, _variable := range environ {
+		_parts := strings.SplitN(_variable, "=", 2)
+		if len(_parts) == 2 {
+			_env[_parts[0]] = _parts[1]
+		}
+	}
+
+	switch {
+	case _env["GITHUB_ACTIONS"] == "true":
+		return github(_env)
+	case _env["GITLAB_CI"] == "true":
+		return gitlab(_env)
+	case _env["BUILDKITE"] == "true":
+		return buildkite(_env)
+	case _env["CIRCLECI"] == "true":
+		return circleci(_env)
+	case _env["JENKINS_URL"] != "":
+		return jenkins(_env)
+	}
+
+	return nil
+} // Parse()
+
+func (c *ci) Provider() string    { return c.provider }
+func (c *ci) Branch() string      { return c.branch }
+func (c *ci) PullRequest() string { return c.pr }
+func (c *ci) Pipeline() string    { return c.pipeline }
+func (c *ci) URL() string         { return c.url }
+func (c *ci) Commit() string      { return c.commit }
+
+//
+// provider environments
+//
+
+// github returns the GitHub Actions environment
+func github(env map[string]string) CI {
+	_ci := &ci{
+		provider: GITHUB,
+		pipeline: env["GITHUB_RUN_ID"],
+		commit:   env["GITHUB_SHA"],
+	}
+
+	// pull request builds have the source branch in GITHUB_HEAD_REF and a
+	// ref of refs/pull/<number>/merge
+	_ref := env["GITHUB_REF"]
+	if env["GITHUB_HEAD_REF"] != "" {
+		_ci.branch = env["GITHUB_HEAD_REF"]
+	} else if strings.HasPrefix(_ref, "refs/heads/") {
+		_ci.branch = strings.TrimPrefix(_ref, "refs/heads/")
+	} else if _ref == "" && env["GITHUB_REF_TYPE"] == "branch" {
+		_ci.branch = env["GITHUB_REF_NAME"]
+	}
+	if strings.HasPrefix(_ref, "refs/pull/") {
+		_ci.pr = strings.SplitN(strings.TrimPrefix(_ref, "refs/pull/"), "/", 2)[0]
+	}
+
+	// construct the URL of the workflow run
+	if env["GITHUB_SERVER_URL"] != "" && env["GITHUB_REPOSITORY"] != "" &&
+		_ci.pipeline != "" {
+		_ci.url = strings.TrimSuffix(env["GITHUB_SERVER_URL"], "/") + "/" +
+			env["GITHUB_REPOSITORY"] + "/actions/runs/" + _ci.pipeline
+	}
+
+	return _ci
+} // github()
+
+// gitlab returns the GitLab CI environment
+func gitlab(env map[string]string) CI {
+	_ci := &ci{
+		provider: GITLAB,
+		pr:       env["CI_MERGE_REQUEST_IID"],
+		pipeline: env["CI_PIPELINE_ID"],
+		url:      env["CI_PIPELINE_URL"],
+		commit:   env["CI_COMMIT_SHA"],
+	}
+
+	// CI_COMMIT_BRANCH is not set for merge request or tag pipelines
+	switch {
+	case env["CI_COMMIT_BRANCH"] != "":
+		_ci.branch = env["CI_COMMIT_BRANCH"]
+	case env["CI_MERGE_REQUEST_SOURCE_BRANCH_NAME"] != "":
+		_ci.branch = env["CI_MERGE_REQUEST_SOURCE_BRANCH_NAME"]
+	case env["CI_COMMIT_TAG"] == "":
+		_ci.branch = env["CI_COMMIT_REF_NAME"]
+	}
+
+	return _ci
+} // gitlab()
+
+// jenkins returns the Jenkins environment
+func jenkins(env map[string]string) CI {
+	_ci := &ci{
+		provider: JENKINS,
+		pr:       env["CHANGE_ID"],
+		pipeline: env["BUILD_NUMBER"],
+		url:      env["BUILD_URL"],
+		commit:   env["GIT_COMMIT"],
+	}
+
+	// multibranch pipelines set BRANCH_NAME (and CHANGE_BRANCH for pull
+	// requests), while the git plugin sets GIT_BRANCH to the remote branch
+	switch {
+	case env["CHANGE_BRANCH"] != "":
+		_ci.branch = env["CHANGE_BRANCH"]
+	case env["BRANCH_NAME"] != "":
+		_ci.branch = env["BRANCH_NAME"]
+	case env["GIT_BRANCH"] != "":
+		_parts := strings.SplitN(env["GIT_BRANCH"], "/", 2)
+		if env["GIT_LOCAL_BRANCH"] != "" {
+			_ci.branch = env["GIT_LOCAL_BRANCH"]
+		} else {
+			_ci.branch = _parts[len(_parts)-1]
+		}
+	}
+
+	return _ci
+} // jenkins()
+
+// buildkite returns the Buildkite environment
+func buildkite(env map[string]string) CI {
+	_ci := &ci{
+		provider: BUILDKITE,
+		branch:   env["BUILDKITE_BRANCH"],
+		pipeline: env["BUILDKITE_BUILD_NUMBER"],
+		url:      env["BUILDKITE_BUILD_URL"],
+		commit:   env["BUILDKITE_COMMIT"],
+	}
+
+	// BUILDKITE_PULL_REQUEST is "false" for non-pull request builds
+	if _pr := env["BUILDKITE_PULL_REQUEST"]; _pr != "false" {
+		_ci.pr = _pr
+	}
+
+	return _ci
+} // buildkite()
+
+// circleci returns the CircleCI environment
+func circleci(env map[string]string) CI {
+	_ci := &ci{
+		provider: CIRCLECI,
+		branch:   env["CIRCLE_BRANCH"],
+		pr:       env["CIRCLE_PR_NUMBER"],
+		pipeline: env["CIRCLE_BUILD_NUM"],
+		url:      env["CIRCLE_BUILD_URL"],
+		commit:   env["CIRCLE_SHA1"],
+	}
+
+	// CIRCLE_PR_NUMBER is only set for builds of forked pull requests,
+	// otherwise the number is the last element of the pull request URL
+	if _ci.pr == "" && env["CIRCLE_PULL_REQUEST"] != "" {
+		_ci.pr = path.Base(env["CIRCLE_PULL_REQUEST"])
+	}
+
+	return _ci
+} // circleci()
+
+// ensure ci implements the CI interface
+var _ CI = &ci{}
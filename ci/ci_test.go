@@ -0,0 +1,219 @@
+package ci_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/denormal/go-gitinfo/ci"
+)
+
+type _test struct {
+	environ  []string
+	provider string
+	branch   string
+	pr       string
+	pipeline string
+	url      string
+}
+
+var _TESTS = []_test{
+	// not CI
+	{[]string{"HOME=/home/user", "BRANCH_NAME=main"}, "", "", "", "", ""},
+	{[]string{"GITHUB_ACTIONS=false"}, "", "", "", "", ""},
+	// GitHub Actions
+	{
+		[]string{
+			"GITHUB_ACTIONS=true",
+			"GITHUB_REF=refs/heads/feature/x",
+			"GITHUB_REF_NAME=feature/x",
+			"GITHUB_REF_TYPE=branch",
+			"GITHUB_HEAD_REF=",
+			"GITHUB_RUN_ID=1234",
+			"GITHUB_SERVER_URL=https://github.com",
+			"GITHUB_REPOSITORY=org/repo",
+		},
+		ci.GITHUB, "feature/x", "", "1234",
+		"https://github.com/org/repo/actions/runs/1234",
+	},
+	{
+		[]string{
+			"GITHUB_ACTIONS=true",
+			"GITHUB_REF=refs/pull/42/merge",
+			"GITHUB_REF_NAME=42/merge",
+			"GITHUB_HEAD_REF=fix",
+			"GITHUB_RUN_ID=1235",
+		},
+		ci.GITHUB, "fix", "42", "1235", "",
+	},
+	{
+		[]string{
+			"GITHUB_ACTIONS=true",
+			"GITHUB_REF=refs/tags/v1.0.0",
+			"GITHUB_REF_NAME=v1.0.0",
+			"GITHUB_REF_TYPE=tag",
+			"GITHUB_RUN_ID=1236",
+		},
+		ci.GITHUB, "", "", "1236", "",
+	},
+	// GitLab CI
+	{
+		[]string{
+			"GITLAB_CI=true",
+			"CI_COMMIT_BRANCH=main",
+			"CI_COMMIT_REF_NAME=main",
+			"CI_PIPELINE_ID=99",
+			"CI_PIPELINE_URL=https://gitlab.com/group/repo/-/pipelines/99",
+		},
+		ci.GITLAB, "main", "", "99",
+		"https://gitlab.com/group/repo/-/pipelines/99",
+	},
+	{
+		[]string{
+			"GITLAB_CI=true",
+			"CI_COMMIT_REF_NAME=feature",
+			"CI_MERGE_REQUEST_IID=7",
+			"CI_MERGE_REQUEST_SOURCE_BRANCH_NAME=feature",
+			"CI_PIPELINE_ID=100",
+		},
+		ci.GITLAB, "feature", "7", "100", "",
+	},
+	{
+		[]string{
+			"GITLAB_CI=true",
+			"CI_COMMIT_REF_NAME=v1.0.0",
+			"CI_COMMIT_TAG=v1.0.0",
+			"CI_PIPELINE_ID=101",
+		},
+		ci.GITLAB, "", "", "101", "",
+	},
+	// Jenkins
+	{
+		[]string{
+			"JENKINS_URL=https://jenkins.example.com/",
+			"BRANCH_NAME=PR-5",
+			"CHANGE_BRANCH=feature",
+			"CHANGE_ID=5",
+			"BUILD_NUMBER=17",
+			"BUILD_URL=https://jenkins.example.com/job/repo/PR-5/17/",
+		},
+		ci.JENKINS, "feature", "5", "17",
+		"https://jenkins.example.com/job/repo/PR-5/17/",
+	},
+	{
+		[]string{
+			"JENKINS_URL=https://jenkins.example.com/",
+			"BRANCH_NAME=main",
+			"BUILD_NUMBER=18",
+		},
+		ci.JENKINS, "main", "", "18", "",
+	},
+	{
+		[]string{
+			"JENKINS_URL=https://jenkins.example.com/",
+			"GIT_BRANCH=origin/release/1.x",
+			"BUILD_NUMBER=19",
+		},
+		ci.JENKINS, "release/1.x", "", "19", "",
+	},
+	// Buildkite
+	{
+		[]string{
+			"BUILDKITE=true",
+			"BUILDKITE_BRANCH=main",
+			"BUILDKITE_PULL_REQUEST=false",
+			"BUILDKITE_BUILD_NUMBER=3",
+			"BUILDKITE_BUILD_URL=https://buildkite.com/org/repo/builds/3",
+		},
+		ci.BUILDKITE, "main", "", "3", "https://buildkite.com/org/repo/builds/3",
+	},
+	{
+		[]string{
+			"BUILDKITE=true",
+			"BUILDKITE_BRANCH=feature",
+			"BUILDKITE_PULL_REQUEST=12",
+			"BUILDKITE_BUILD_NUMBER=4",
+		},
+		ci.BUILDKITE, "feature", "12", "4", "",
+	},
+	// CircleCI
+	{
+		[]string{
+			"CIRCLECI=true",
+			"CIRCLE_BRANCH=feature",
+			"CIRCLE_PULL_REQUEST=https://github.com/org/repo/pull/8",
+			"CIRCLE_BUILD_NUM=55",
+			"CIRCLE_BUILD_URL=https://circleci.com/gh/org/repo/55",
+		},
+		ci.CIRCLECI, "feature", "8", "55", "https://circleci.com/gh/org/repo/55",
+	},
+	{
+		[]string{
+			"CIRCLECI=true",
+			"CIRCLE_BRANCH=pull/9",
+			"CIRCLE_PR_NUMBER=9",
+			"CIRCLE_BUILD_NUM=56",
+		},
+		ci.CIRCLECI, "pull/9", "9", "56", "",
+	},
+}
+
+func TestParse(t *testing.T) {
+	for _, _test := range _TESTS {
+		_ci := ci.Parse(_test.environ)
+		if _test.provider == "" {
+			if _ci != nil {
+				t.Fatalf(
+					"%v: unexpected CI; expected nil, got %q",
+					_test.environ, _ci.Provider(),
+				)
+			}
+			continue
+		} else if _ci == nil {
+			t.Fatalf(
+				"%v: unexpected nil CI; expected %q",
+				_test.environ, _test.provider,
+			)
+		}
+
+		for _, _check := range [][3]string{
+			{"Provider()", _test.provider, _ci.Provider()},
+			{"Branch()", _test.branch, _ci.Branch()},
+			{"PullRequest()", _test.pr, _ci.PullRequest()},
+			{"Pipeline()", _test.pipeline, _ci.Pipeline()},
+			{"URL()", _test.url, _ci.URL()},
+		} {
+			if _check[1] != _check[2] {
+				t.Fatalf(
+					"%v: unexpected %s; expected %q, got %q",
+					_test.environ, _check[0], _check[1], _check[2],
+				)
+			}
+		}
+	}
+} // TestParse()
+
+func TestCommit(t *testing.T) {
+	_commit := "0123456789abcdef0123456789abcdef01234567"
+	for _environ, _provider := range map[string]string{
+		"GITHUB_ACTIONS=true,GITHUB_SHA=":                      ci.GITHUB,
+		"GITLAB_CI=true,CI_COMMIT_SHA=":                        ci.GITLAB,
+		"JENKINS_URL=https://jenkins.example.com/,GIT_COMMIT=": ci.JENKINS,
+		"BUILDKITE=true,BUILDKITE_COMMIT=":                     ci.BUILDKITE,
+		"CIRCLECI=true,CIRCLE_SHA1=":                           ci.CIRCLECI,
+	} {
+		_ci := ci.Parse(strings.Split(_environ+_commit, ","))
+		if _ci == nil || _ci.Provider() != _provider {
+			t.Fatalf("%s: expected %q CI; got %v", _environ, _provider, _ci)
+		} else if _ci.Commit() != _commit {
+			t.Fatalf(
+				"%s: unexpected Commit(); expected %q, got %q",
+				_environ, _commit, _ci.Commit(),
+			)
+		}
+	}
+
+	// the commit of CI instances created with New is not known
+	if _ci := ci.New(ci.GITHUB, "main", "", "1", ""); _ci.Commit() != "" {
+		t.Fatalf("unexpected Commit(); expected %q, got %q", "", _ci.Commit())
+	}
+} // TestCommit()
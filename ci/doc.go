@@ -0,0 +1,10 @@
+/*
+Package ci recognises the continuous integration environment of the current
+process, for GitHub Actions, GitLab CI, Jenkins, Buildkite and CircleCI,
+reporting the branch, pull request, pipeline, build URL and commit of the
+build.
+
+CI checkouts are typically of a detached HEAD, and so the branch being
+built cannot be determined from the working copy alone.
+*/
+package ci
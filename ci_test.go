@@ -0,0 +1,112 @@
+package gitinfo_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/denormal/go-gitinfo"
+	"github.com/denormal/go-gittools"
+)
+
+func TestCI(t *testing.T) {
+	// if we don't have git installed, then skip this test
+	if !gittools.HasGit() {
+		t.Skip("git not installed")
+	}
+
+	_dir := repository(t)
+	defer os.RemoveAll(_dir)
+	_commit := commit(t, _dir, "2024-01-01T00:00:00Z", "initial")
+	git(t, _dir, "checkout", "-q", "-B", "main")
+	git(t, _dir, "checkout", "-q", "--detach", "HEAD")
+
+	// create another working copy, not being built by CI
+	_other := repository(t)
+	defer os.RemoveAll(_other)
+	commit(t, _other, "2024-01-02T00:00:00Z", "other")
+	git(t, _other, "checkout", "-q", "--detach", "HEAD")
+
+	// fake the GitHub Actions environment
+	for _name, _value := range map[string]string{
+		"GITHUB_ACTIONS":    "true",
+		"GITHUB_HEAD_REF":   "",
+		"GITHUB_REF":        "refs/heads/release",
+		"GITHUB_REPOSITORY": "org/repo",
+		"GITHUB_RUN_ID":     "1234",
+		"GITHUB_SERVER_URL": "https://github.com",
+		"GITHUB_SHA":        _commit,
+	} {
+		_reset, _err := env(_name, _value)
+		defer _reset()
+		if _err != nil {
+			t.Fatalf("%s: set failed: %s", _name, _err.Error())
+		}
+	}
+
+	// the CI environment is ignored unless requested, or for working copies
+	// other than the one being built
+	for _, _path := range []string{_dir, _other} {
+		_info, _err := gitinfo.NewWithPath(_path)
+		if _err == nil && _path == _other {
+			_info, _err = gitinfo.NewWithCI(_path, gitinfo.ModifiedOptions{})
+		}
+		if _err != nil {
+			t.Fatalf("unexpected error for %s: %s", _path, _err.Error())
+		}
+		_branch, _err := _info.Branch()
+		if _err != nil {
+			t.Fatalf("unexpected error from Branch(): %s", _err.Error())
+		} else if _branch != "HEAD" {
+			t.Fatalf("unexpected branch; expected %q, got %q", "HEAD", _branch)
+		} else if _info.CI() != nil {
+			t.Fatalf("unexpected CI for %s: %v", _path, _info.CI())
+		} else if _map := _info.Map(); _map[gitinfo.CI_PROVIDER] != "" {
+			t.Fatalf(
+				"unexpected %s for %s: %q",
+				gitinfo.CI_PROVIDER, _path, _map[gitinfo.CI_PROVIDER],
+			)
+		}
+	}
+
+	// the detached HEAD being built should report the CI branch
+	_info, _err := gitinfo.NewWithCI(_dir, gitinfo.ModifiedOptions{})
+	if _err != nil {
+		t.Fatalf("unexpected error from NewWithCI(): %s", _err.Error())
+	}
+	_branch, _err := _info.Branch()
+	if _err != nil {
+		t.Fatalf("unexpected error from Branch(): %s", _err.Error())
+	} else if _branch != "release" {
+		t.Fatalf("unexpected branch; expected %q, got %q", "release", _branch)
+	}
+
+	// the CI details should be included in the map
+	_map := _info.Map()
+	for _k, _v := range map[string]string{
+		gitinfo.BRANCH:          "release",
+		gitinfo.CI_BRANCH:       "release",
+		gitinfo.CI_PIPELINE:     "1234",
+		gitinfo.CI_PROVIDER:     "github",
+		gitinfo.CI_PULL_REQUEST: "",
+		gitinfo.CI_URL:          "https://github.com/org/repo/actions/runs/1234",
+	} {
+		_value, _ok := _map[_k]
+		if !_ok {
+			t.Fatalf("expected map value for %q, none found", _k)
+		} else if _value != _v {
+			t.Fatalf(
+				"unexpected value for %q; expected %q, got %q",
+				_k, _v, _value,
+			)
+		}
+	}
+
+	// the CI branch does not override a checked-out branch
+	git(t, _dir, "checkout", "-q", "main")
+	_branch, _err = _info.Branch()
+	if _err != nil {
+		t.Fatalf("unexpected error from Branch(): %s", _err.Error())
+	} else if _branch != "main" {
+		t.Fatalf("unexpected branch; expected %q, got %q", "main", _branch)
+	}
+} // TestCI()
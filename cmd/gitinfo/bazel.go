@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/denormal/go-gitinfo"
+	"github.com/denormal/go-gittools"
+)
+
+// the prefix Bazel requires of stable workspace status keys
+const _STABLE = "STABLE_"
+
+// bazel outputs the git information as a Bazel workspace status command,
+// as lines of the form "KEY value". The commit and branch are output as
+// stable keys, prefixed with -stable-prefix, so that changes to them
+// invalidate the targets that depend upon them, while the modified state
+// and the commit time (in seconds since the epoch) are output as volatile
+// keys, prefixed with -volatile-prefix. Unknown values, such as the branch
+// of a detached HEAD, or the commit of a working copy without commits, are
+// omitted, while nothing is output for paths not within a working copy. An
+// error is returned only if the git information of a working copy cannot be
+// determined.
+func bazel(out io.Writer, gi gitinfo.GitInfo) error {
+	// have we been given a working copy?
+	if gi.Root() == "" {
+		return nil
+	}
+
+	// determine the stable and volatile values
+	var (
+		_stable   = make(map[string]string)
+		_volatile = make(map[string]string)
+	)
+	//		- a working copy without commits has no commit or branch
+	//		- the branch of a detached HEAD is reported as "HEAD"
+	if !unborn(gi) {
+		_commit, _err := gi.Commit()
+		if _err != nil {
+			return _err
+		} else if _commit != nil {
+			_stable[gitinfo.COMMIT] = _commit.String()
+
+			_time, _err := _commit.Time()
+			if _err != nil {
+				return _err
+			}
+			_volatile["commit.time"] = strconv.FormatInt(_time.Unix(), 10)
+		}
+		_branch, _err := gi.Branch()
+		if _err != nil {
+			return _err
+		} else if _branch != "HEAD" {
+			_stable[gitinfo.BRANCH] = _branch
+		}
+	}
+	_modified, _err := gi.Modified()
+	if _err != nil {
+		return _err
+	}
+	_volatile[gitinfo.MODIFIED] = strconv.FormatBool(_modified)
+
+	// output the keys
+	for _, _keys := range []struct {
+		prefix string
+		values map[string]string
+	}{
+		{*opt.sprefix, _stable},
+		{*opt.vprefix, _volatile},
+	} {
+		for _, _k := range keys(_keys.values) {
+			if _keys.values[_k] != "" {
+				fmt.Fprintf(out, "%s%s %s\n",
+					_keys.prefix, constant(_k), _keys.values[_k],
+				)
+			}
+		}
+	}
+
+	return nil
+} // bazel()
+
+// unborn returns true if the working copy of gi does not have any commits
+func unborn(gi gitinfo.GitInfo) bool {
+	_, _err := gittools.RunInPath(
+		gi.Root(), "rev-parse", "--verify", "--quiet", "HEAD^{commit}",
+	)
+	return _err != nil
+} // unborn()
+
+// prefixes returns an error if the Bazel key prefixes are not valid; stable
+// keys must be prefixed with STABLE_, while volatile keys must not be
+func prefixes(stable, volatile string) error {
+	if !strings.HasPrefix(stable, _STABLE) {
+		return fmt.Errorf(
+			"invalid stable prefix %q; must start with %q", stable, _STABLE,
+		)
+	} else if strings.HasPrefix(volatile, _STABLE) {
+		return fmt.Errorf(
+			"invalid volatile prefix %q; must not start with %q",
+			volatile, _STABLE,
+		)
+	}
+
+	return nil
+} // prefixes()
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestPrefixes(t *testing.T) {
+	for _, _test := range []struct {
+		stable   string
+		volatile string
+		valid    bool
+	}{
+		{"STABLE_GIT_", "GIT_", true},
+		{"STABLE_", "", true},
+		{"STABLE_X_", "BUILD_", true},
+		{"GIT_", "GIT_", false},
+		{"", "GIT_", false},
+		{"stable_", "GIT_", false},
+		{"STABLE_GIT_", "STABLE_GIT_", false},
+		{"STABLE_GIT_", "STABLE_", false},
+	} {
+		_err := prefixes(_test.stable, _test.volatile)
+		if _test.valid && _err != nil {
+			t.Fatalf(
+				"%q, %q: unexpected error: %s",
+				_test.stable, _test.volatile, _err.Error(),
+			)
+		} else if !_test.valid && _err == nil {
+			t.Fatalf(
+				"%q, %q: expected error", _test.stable, _test.volatile,
+			)
+		}
+	}
+} // TestPrefixes()
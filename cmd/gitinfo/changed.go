@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+)
+
+// update writes the content to the file at path, unless the file already
+// holds the same content, ignoring the leading comment header of both
+func update(path string, content []byte) error {
+	_bytes, _err := ioutil.ReadFile(path)
+	if _err == nil && bytes.Equal(body(_bytes), body(content)) {
+		return nil
+	}
+
+	return ioutil.WriteFile(path, content, 0666)
+} // update()
+
+// body returns the content following the leading comment lines, such as
+// the header of generated files
+func body(content []byte) []byte {
+	for commented(content) {
+		_i := bytes.IndexByte(content, '\n')
+		if _i < 0 {
+			return nil
+		}
+		content = content[_i+1:]
+	}
+
+	return content
+} // body()
+
+// commented returns true if the content starts with a comment line of the
+// header of generated files; "//" comments, or "#" comments (other than C
+// preprocessor directives)
+func commented(content []byte) bool {
+	return bytes.HasPrefix(content, []byte("//")) ||
+		bytes.HasPrefix(content, []byte("# ")) ||
+		bytes.HasPrefix(content, []byte("#\n"))
+} // commented()
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/denormal/go-gitinfo"
+)
+
+// changelog outputs the changelog for the given range of commits in the
+// current working copy, as Markdown or JSON:
+//
+//	gitinfo changelog [-json] [from..to]
+func changelog(out io.Writer, args []string) {
+	_flags := flag.NewFlagSet("changelog", flag.ExitOnError)
+	_json := _flags.Bool("json", false, "Output the changelog as JSON.")
+	_flags.Parse(args)
+
+	// have we been given a range?
+	//		- "from..to", "from.." or "to"
+	var _from, _to string
+	if _flags.NArg() > 1 {
+		fail(1, "%s: changelog: too many arguments\n", exe())
+	} else if _flags.NArg() == 1 {
+		_parts := strings.SplitN(_flags.Arg(0), "..", 2)
+		if len(_parts) == 2 {
+			_from, _to = _parts[0], _parts[1]
+		} else {
+			_to = _parts[0]
+		}
+	}
+
+	// generate the changelog
+	_info, _err := gitinfo.New()
+	if _err != nil {
+		fail(2, "%s: changelog: error: %s\n", exe(), _err.Error())
+	}
+	_changelog, _err := _info.Changelog(_from, _to)
+	if _err != nil {
+		fail(2, "%s: changelog: error: %s\n", exe(), _err.Error())
+	}
+
+	// render the changelog
+	if *_json {
+		_bytes, _err := _changelog.JSON()
+		if _err != nil {
+			fail(3, "%s: changelog: error: %s\n", exe(), _err.Error())
+		}
+		fmt.Fprintln(out, string(_bytes))
+	} else {
+		fmt.Fprint(out, _changelog.Markdown())
+	}
+} // changelog()
+
+func init() {
+	register("changelog", "changelog [-json] [from..to]", changelog)
+} // init()
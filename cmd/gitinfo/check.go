@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/denormal/go-gitinfo/stale"
+)
+
+// check compares the git information of the given file, as generated by
+// "gitinfo -X", with the working copy containing the file, displaying the
+// fields that differ and exiting with an error if the file is stale:
+//
+//	gitinfo check [-all] <file>
+func check(out io.Writer, args []string) {
+	_flags := flag.NewFlagSet("check", flag.ExitOnError)
+	_all := _flags.Bool("all", false,
+		"Compare all fields, including those describing the environment "+
+			"(e.g. path\n"+
+			"\tand user.*) rather than the working copy.",
+	)
+	_flags.Parse(args)
+	if _flags.NArg() != 1 {
+		fail(1, "%s: check: expected <file>\n", exe())
+	}
+	_file := _flags.Arg(0)
+
+	// compare the generated and live git information
+	_diffs, _err := stale.Check(_file, *_all)
+	if _err != nil {
+		fail(2, "%s: check: %s: %s\n", exe(), _file, _err.Error())
+	} else if len(_diffs) == 0 {
+		return
+	}
+
+	// display the differences
+	fmt.Fprintf(out, "--- %s\n+++ %s\n", _file, "live")
+	for _, _diff := range _diffs {
+		fmt.Fprintf(out, "-%s = %s\n", _diff.Field, _diff.Generated)
+		fmt.Fprintf(out, "+%s = %s\n", _diff.Field, _diff.Live)
+	}
+	fail(4, "%s: check: %s is stale\n", exe(), _file)
+} // check()
+
+func init() {
+	register("check", "check [-all] <file>", check)
+} // init()
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"io"
+	"sort"
+)
+
+// command is the signature of a gitinfo command, receiving the output
+// destination and the command arguments
+type command func(out io.Writer, args []string)
+
+// subcommand describes a registered gitinfo command
+type subcommand struct {
+	usage string  // the command synopsis (e.g. "next-version [path]")
+	run   command // the command implementation
+}
+
+// commands maps the gitinfo command names to their implementations, as
+// registered by the files implementing them
+var commands = map[string]*subcommand{}
+
+// register adds the named command to the set of gitinfo commands
+func register(name, usage string, c command) {
+	commands[name] = &subcommand{usage: usage, run: c}
+} // register()
+
+// names returns the sorted list of command names
+func names() []string {
+	_names := make([]string, 0, len(commands))
+	for _name, _ := range commands {
+		_names = append(_names, _name)
+	}
+	sort.Strings(_names)
+
+	return _names
+} // names()
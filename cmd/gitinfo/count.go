@@ -0,0 +1,33 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/denormal/go-gitinfo"
+)
+
+// count updates the count field of the given map, if present, for the
+// commit count options given on the command line, recording the options in
+// the count.options field so that the count is not mistaken for the count
+// of all commits
+func count(gi gitinfo.GitInfo, m map[string]string) error {
+	if _, _ok := m[gitinfo.COUNT]; !_ok {
+		return nil
+	} else if !*opt.first && !*opt.since {
+		return nil
+	}
+
+	// recount the commits with the given options
+	_options := gitinfo.CountOptions{
+		FirstParent: *opt.first,
+		SinceTag:    *opt.since,
+	}
+	_count, _err := gi.CommitCount(_options)
+	if _err != nil {
+		return _err
+	}
+	m[gitinfo.COUNT] = strconv.Itoa(_count)
+	m[gitinfo.COUNT_OPTIONS] = _options.String()
+
+	return nil
+} // count()
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/denormal/go-gitinfo"
+	"github.com/denormal/go-gitinfo/stale"
+	"github.com/denormal/go-gittools"
+)
+
+// diff compares the git information of two snapshots, each of which may be
+// a working copy path, a JSON object of the git information fields, a file
+// generated by "gitinfo -X", or a compiled binary, displaying the fields
+// known to both snapshots that differ, and the log of the commits between
+// them if both commits are known to the current working copy:
+//
+//	gitinfo diff <a> <b>
+func diff(out io.Writer, args []string) {
+	_flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	_flags.Parse(args)
+	if _flags.NArg() != 2 {
+		fail(1, "%s: diff: expected <a> <b>\n", exe())
+	}
+
+	// load the snapshots
+	_a, _b := _flags.Arg(0), _flags.Arg(1)
+	_amap, _err := snapshot(_a)
+	if _err != nil {
+		fail(2, "%s: diff: error: %s: %s\n", exe(), _a, _err.Error())
+	}
+	_bmap, _err := snapshot(_b)
+	if _err != nil {
+		fail(2, "%s: diff: error: %s: %s\n", exe(), _b, _err.Error())
+	}
+
+	// display the differences of the fields known to both snapshots
+	_header := false
+	_diffs := gitinfo.Diff(gitinfo.Build(_amap), gitinfo.Build(_bmap))
+	for _, _diff := range _diffs {
+		_, _aok := _amap[_diff.Field]
+		_, _bok := _bmap[_diff.Field]
+		if !_aok || !_bok {
+			continue
+		} else if !_header {
+			fmt.Fprintf(out, "--- %s\n+++ %s\n", _a, _b)
+			_header = true
+		}
+		fmt.Fprintf(out, "-%s = %s\n", _diff.Field, _diff.A)
+		fmt.Fprintf(out, "+%s = %s\n", _diff.Field, _diff.B)
+	}
+
+	// display the commits between the snapshots
+	//		- snapshot commits must not be mistaken for git options
+	_from, _to := _amap[gitinfo.COMMIT], _bmap[gitinfo.COMMIT]
+	if _from == "" || _to == "" || _from == _to ||
+		strings.HasPrefix(_from, "-") || strings.HasPrefix(_to, "-") {
+		return
+	}
+	_info, _err := gitinfo.New()
+	if _err != nil || _info.Root() == "" {
+		return
+	}
+	for _, _commit := range []string{_from, _to} {
+		_, _err = gittools.RunInPath(
+			_info.Root(), "cat-file", "-e", _commit+"^{commit}",
+		)
+		if _err != nil {
+			return
+		}
+	}
+	_log, _err := gittools.RunInPath(
+		_info.Root(), "log", "--oneline", _from+".."+_to, "--",
+	)
+	if _err != nil {
+		fail(3, "%s: diff: error: %s\n", exe(), _err.Error())
+	}
+	fmt.Fprintf(out, "\n%s..%s\n%s", _from, _to, _log)
+} // diff()
+
+// snapshot returns the git information fields of the given working copy
+// path, JSON object file, generated Go file or compiled binary
+func snapshot(path string) (map[string]string, error) {
+	_stat, _err := os.Stat(path)
+	if _err != nil {
+		return nil, _err
+	} else if _stat.IsDir() {
+		_info, _err := gitinfo.NewWithPath(path)
+		if _err != nil {
+			return nil, _err
+		} else if _info.Root() == "" {
+			return nil, gitinfo.MissingWorkingCopyError
+		}
+		return _info.Map(), nil
+	}
+
+	// do we have a JSON object?
+	_bytes, _err := ioutil.ReadFile(path)
+	if _err != nil {
+		return nil, _err
+	}
+	var _map map[string]string
+	if json.Unmarshal(_bytes, &_map) == nil {
+		return _map, nil
+	}
+
+	// do we have a generated Go file?
+	if strings.HasSuffix(path, ".go") {
+		_file, _err := parser.ParseFile(token.NewFileSet(), path, _bytes, 0)
+		if _err != nil {
+			return nil, _err
+		}
+		_, _map = stale.Generated(_file)
+		if _map == nil {
+			return nil, stale.MissingBuildError
+		}
+		return _map, nil
+	}
+
+	// otherwise, we should have a compiled binary
+	return gitinfo.Inspect(path)
+} // snapshot()
+
+func init() {
+	register("diff", "diff <a> <b>", diff)
+} // init()
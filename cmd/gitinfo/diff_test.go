@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/denormal/go-gitinfo"
+	"github.com/denormal/go-gittools"
+)
+
+// the git information of this test binary, as generated by gitinfo
+var _diff = [...][2]string{
+	{gitinfo.BRANCH, "diff"},
+	{gitinfo.COMMIT, "0123456789abcdef0123456789abcdef01234567"},
+}
+
+func TestDiff(t *testing.T) {
+	// if we don't have git installed, then skip this test
+	if !gittools.HasGit() {
+		t.Skip("git not installed")
+	}
+
+	_dir, _err := ioutil.TempDir("", "")
+	if _err != nil {
+		t.Fatalf("unable to create temporary directory: %s", _err.Error())
+	}
+	defer os.RemoveAll(_dir)
+	_dir, _ = filepath.EvalSymlinks(_dir)
+	_repo := filepath.Join(_dir, "repo")
+	run(t, _dir, "init", "-q", _repo)
+	run(t, _repo, "symbolic-ref", "HEAD", "refs/heads/main")
+	_first := commit(t, _repo, "first")
+	_second := commit(t, _repo, "second")
+
+	// the commit log is taken from the current working copy
+	_wd, _err := os.Getwd()
+	if _err != nil {
+		t.Fatalf("unable to determine working directory: %s", _err.Error())
+	}
+	defer os.Chdir(_wd)
+	_err = os.Chdir(_repo)
+	if _err != nil {
+		t.Fatalf("unable to change directory: %s", _err.Error())
+	}
+
+	// compare a JSON object with a generated Go file
+	_json := filepath.Join(_dir, "a.json")
+	_err = ioutil.WriteFile(_json, []byte(
+		`{"branch":"main","commit":"`+_first+`","modified":"false"}`,
+	), 0644)
+	if _err != nil {
+		t.Fatalf("unable to write %s: %s", _json, _err.Error())
+	}
+	_buffer := new(bytes.Buffer)
+	generate(_buffer, map[string]string{
+		gitinfo.BRANCH:   "main",
+		gitinfo.COMMIT:   _second,
+		gitinfo.MODIFIED: "true",
+	}, "main", "git", false, nil, time.Now())
+	_go := filepath.Join(_dir, "b.go")
+	_err = ioutil.WriteFile(_go, _buffer.Bytes(), 0644)
+	if _err != nil {
+		t.Fatalf("unable to write %s: %s", _go, _err.Error())
+	}
+	//		- both commits are known, so the log is displayed
+	_short := strings.TrimSpace(
+		run(t, _repo, "rev-parse", "--short", _second),
+	)
+	differences(t, _json, _go,
+		"--- "+_json+"\n+++ "+_go+"\n",
+		"\n-commit = "+_first+"\n+commit = "+_second+"\n",
+		"\n-modified = false\n+modified = true\n",
+		"\n\n"+_first+".."+_second+"\n"+_short+" second\n",
+	)
+
+	// compare a compiled binary with a working copy
+	//		- the commit of the binary is not known, so there is no log
+	if len(_diff) == 0 {
+		t.Fatal("unexpected empty git information")
+	}
+	_binary, _err := os.Executable()
+	if _err != nil {
+		t.Skipf("unable to determine test binary: %s", _err.Error())
+	}
+	_output := differences(t, _binary, _repo,
+		"\n-branch = diff\n+branch = main\n",
+		"\n-commit = "+_diff[1][1]+"\n+commit = "+_second+"\n",
+	)
+	if strings.Contains(_output, "..") {
+		t.Fatalf("unexpected commit log:\n%s", _output)
+	}
+} // TestDiff()
+
+//
+// helper functions
+//
+
+func differences(t *testing.T, a, b string, expected ...string) string {
+	_buffer := new(bytes.Buffer)
+	diff(_buffer, []string{a, b})
+	_output := _buffer.String()
+	for _, _expected := range expected {
+		if !strings.Contains(_output, _expected) {
+			t.Fatalf(
+				"%s %s: expected %q; got\n%s", a, b, _expected, _output,
+			)
+		}
+	}
+
+	return _output
+} // differences()
+
+func commit(t *testing.T, dir, msg string) string {
+	run(t, dir,
+		"-c", "user.name=gitinfo", "-c", "user.email=gitinfo@example.com",
+		"-c", "commit.gpgsign=false",
+		"commit", "-q", "--allow-empty", "-m", msg,
+	)
+
+	return strings.TrimSpace(run(t, dir, "rev-parse", "HEAD"))
+} // commit()
+
+func run(t *testing.T, dir string, args ...string) string {
+	_output, _err := gittools.RunInPath(dir, args...)
+	if _err != nil {
+		t.Fatalf("git %s: %s", strings.Join(args, " "), _err.Error())
+	}
+
+	return string(_output)
+} // run()
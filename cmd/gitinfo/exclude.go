@@ -0,0 +1,37 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/denormal/go-gitinfo"
+)
+
+// exclude returns the literal pathspec of the given file, relative to the
+// root of the working copy, so that the file may be excluded from the
+// modifications of the working copy, or nil if the file is not within the
+// working copy
+func exclude(gi gitinfo.GitInfo, file string) []string {
+	_root := gi.Root()
+	if _root == "" {
+		return nil
+	}
+
+	// the working copy root has its symbolic links resolved
+	_path, _err := filepath.Abs(file)
+	if _err != nil {
+		return nil
+	}
+	_dir, _err := filepath.EvalSymlinks(filepath.Dir(_path))
+	if _err != nil {
+		return nil
+	}
+	_path = filepath.Join(_dir, filepath.Base(_path))
+	_relative, _err := filepath.Rel(_root, _path)
+	if _err != nil || _relative == ".." ||
+		strings.HasPrefix(_relative, ".."+string(filepath.Separator)) {
+		return nil
+	}
+
+	return []string{":(literal)" + filepath.ToSlash(_relative)}
+} // exclude()
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"io"
+	"sort"
+
+	"github.com/denormal/go-gitinfo"
+)
+
+// the alternative output formats of the git information
+var _FORMATS = map[string]func(io.Writer, gitinfo.GitInfo) error{
+	"bazel":          bazel,
+	"oci-build-args": ociBuildArgs,
+	"oci-dockerfile": ociDockerfile,
+	"oci-labels":     ociLabels,
+}
+
+// formats returns the ordered names of the alternative output formats of
+// the git information
+func formats() []string {
+	_formats := make([]string, 0, len(_FORMATS))
+	for _format, _ := range _FORMATS {
+		_formats = append(_formats, _format)
+	}
+	sort.Strings(_formats)
+
+	return _formats
+} // formats()
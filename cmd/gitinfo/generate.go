@@ -6,6 +6,7 @@ import (
 	"os"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,21 +15,25 @@ import (
 	"github.com/denormal/go-gitinfo"
 )
 
-func generate(out io.Writer, m map[string]string, pkg, v string, runtime bool) {
-	// extract the command line argument used in this invocation
-	_cmd := strings.Join(os.Args, " ")
+// the environment variable giving the timestamp of reproducible builds
+const _SOURCE_DATE_EPOCH = "SOURCE_DATE_EPOCH"
 
-	// generate a string representation of the map
+func generate(
+	out io.Writer,
+	m map[string]string,
+	pkg, v string,
+	runtime bool,
+	exclude []string,
+	when time.Time,
+) {
+	// generate a string representation of the map as field name and value
+	// pairs
 	//		- we use ordered strings to ensure repeatability
-	_keys := make([]string, 0, len(m))
-	for _k, _ := range m {
-		_keys = append(_keys, _k)
-	}
-	sort.Strings(_keys)
-
-	_map := ""
-	for _, _k := range _keys {
-		_map = _map + fmt.Sprintf("%q : %q,\n", _k, m[_k])
+	//		- a static array allows the fields to be found within the compiled
+	//		  binary (see gitinfo.Inspect())
+	_pairs := ""
+	for _, _k := range keys(m) {
+		_pairs = _pairs + fmt.Sprintf("{%q, %q},\n", _k, m[_k])
 	}
 
 	// determine the import path of go-gitinfo
@@ -37,33 +42,57 @@ func generate(out io.Writer, m map[string]string, pkg, v string, runtime bool) {
 	_import := _ref.PkgPath()
 
 	// should we include runtime checking of the git information?
+	//		- modifications excluded from the compiled git information, such
+	//		  as of the generated file, are excluded at runtime
+	_imports := fmt.Sprintf("%q", _import)
 	_runtime := ""
-	if runtime {
+	if runtime && len(exclude) == 0 {
 		_runtime = fmt.Sprintf("%s, _ = gitinfo.Here()", v)
+	} else if runtime {
+		_pathspecs := ""
+		for _, _pathspec := range exclude {
+			_pathspecs = _pathspecs + fmt.Sprintf("%q,\n", _pathspec)
+		}
+		_imports = "\"runtime\"\n\n" + _imports
+		_runtime = fmt.Sprintf(
+			`if _, _file, _, _ok := runtime.Caller(0); _ok {
+                %s, _ = gitinfo.NewWithOptions(
+                    _file,
+                    gitinfo.ModifiedOptions{Exclude: []string{
+                        %s
+                    }},
+                )
+            }`,
+			v, _pathspecs,
+		)
 	}
 
 	// generate the package definition
 	_src := fmt.Sprintf(
-		`
-// generated by %s
-//           on %s
-//   DO NOT EDIT; local changes will be overridden
-//
-//   %% %s
+		`%s
 package %s
 
-import %q
+import (
+    %s
+)
+
+// the git information of %s as field name and value pairs
+var _%s = [...][2]string{
+    %s
+}
 
 func init() { %s
     if %s == nil {
-        %s = gitinfo.Build( map[string]string{
-                %s
-             } )
+        _map := make(map[string]string, len(_%s))
+        for _, _field := range _%s {
+            _map[_field[0]] = _field[1]
+        }
+        %s = gitinfo.Build(_map)
     }
 }`,
-		tag(true), time.Now().UTC().String(), _cmd,
-		pkg, _import,
-		_runtime, v, v, _map,
+		header(when), pkg, _imports,
+		v, v, _pairs,
+		_runtime, v, v, v, v,
 	)
 
 	// apply standard formatting
@@ -74,3 +103,64 @@ func init() { %s
 
 	fmt.Fprint(out, string(_bytes))
 } // generate()
+
+// header returns the comment header of generated files, recording the
+// command and the given time of generation
+func header(when time.Time) string {
+	// extract the command line argument used in this invocation
+	//		- the command path may vary between systems
+	_cmd := strings.Join(append([]string{exe()}, os.Args[1:]...), " ")
+
+	return fmt.Sprintf(
+		`
+// generated by %s
+//           on %s
+//   DO NOT EDIT; local changes will be overridden
+//
+//   %% %s`,
+		tag(true), when.UTC().String(), _cmd,
+	)
+} // header()
+
+// keys returns the ordered keys of the given map
+func keys(m map[string]string) []string {
+	_keys := make([]string, 0, len(m))
+	for _k, _ := range m {
+		_keys = append(_keys, _k)
+	}
+	sort.Strings(_keys)
+
+	return _keys
+} // keys()
+
+// timestamp returns the time recorded in generated files; the time of the
+// HEAD commit if commit is true, otherwise the time given by the
+// SOURCE_DATE_EPOCH environment variable, or the current time if this is
+// not set
+func timestamp(gi gitinfo.GitInfo, commit bool) (time.Time, error) {
+	if commit {
+		_commit, _err := gi.Commit()
+		if _err != nil {
+			return time.Time{}, _err
+		} else if _commit != nil {
+			_time, _err := _commit.Time()
+			if _err != nil || !_time.IsZero() {
+				return _time, _err
+			}
+		}
+	}
+
+	// have we been given the timestamp in the environment?
+	_epoch := os.Getenv(_SOURCE_DATE_EPOCH)
+	if _epoch == "" {
+		return time.Now(), nil
+	}
+	_seconds, _err := strconv.ParseInt(_epoch, 10, 64)
+	if _err != nil {
+		return time.Time{}, fmt.Errorf(
+			"invalid %s %q: %s", _SOURCE_DATE_EPOCH, _epoch, _err.Error(),
+		)
+	}
+
+	return time.Unix(_seconds, 0), nil
+} // timestamp()
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"strings"
+
+	"github.com/denormal/go-gitinfo"
+)
+
+// inspect displays the git information embedded within a compiled Go
+// binary, as recorded by the go tool, set with "-ldflags -X", or generated
+// by "gitinfo -X":
+//
+//	gitinfo inspect [-s] [-f fields] <binary>
+func inspect(out io.Writer, args []string) {
+	_flags := flag.NewFlagSet("inspect", flag.ExitOnError)
+	_short := _flags.Bool("s", false,
+		"Short display; only output field values.",
+	)
+	_fields := _flags.String("f", "",
+		"Output just the given `fields` (comma-separated).",
+	)
+	_flags.Parse(args)
+	if _flags.NArg() != 1 {
+		fail(1, "%s: inspect: expected <binary>\n", exe())
+	}
+
+	// extract the git information from the binary
+	_map, _err := gitinfo.Inspect(_flags.Arg(0))
+	if _err != nil {
+		fail(2, "%s: inspect: error: %s: %s\n",
+			exe(), _flags.Arg(0), _err.Error(),
+		)
+	}
+
+	// should we only output certain fields?
+	//		- the embedded patch is only displayed on request
+	var _f []string
+	if *_fields != "" {
+		_f = strings.Split(*_fields, ",")
+	} else {
+		delete(_map, gitinfo.MODIFIED_PATCH)
+	}
+	display(out, _map, *_short, _f)
+} // inspect()
+
+func init() {
+	register("inspect", "inspect [-s] [-f fields] <binary>", inspect)
+} // init()
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// the generators of the git information for languages other than Go
+var _LANGUAGES = map[string]func(io.Writer, map[string]string, time.Time){
+	"c":      c,
+	"java":   properties,
+	"js":     javascript,
+	"python": python,
+	"rust":   rust,
+	"ts":     javascript,
+}
+
+// languages returns the ordered names of the languages for which the git
+// information may be generated, other than Go
+func languages() []string {
+	_languages := make([]string, 0, len(_LANGUAGES))
+	for _language, _ := range _LANGUAGES {
+		_languages = append(_languages, _language)
+	}
+	sort.Strings(_languages)
+
+	return _languages
+} // languages()
+
+// c generates a C header defining the git information as macros
+func c(out io.Writer, m map[string]string, when time.Time) {
+	fmt.Fprintln(out, comment(header(when), "//"))
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "#ifndef GITINFO_H")
+	fmt.Fprintln(out, "#define GITINFO_H")
+	fmt.Fprintln(out)
+	for _, _k := range keys(m) {
+		_value := literal(m[_k], octal)
+		if _BOOLEANS[_k] {
+			_value = "0"
+			if _bool, _ := strconv.ParseBool(m[_k]); _bool {
+				_value = "1"
+			}
+		} else if _INTEGERS[_k] {
+			_value = integer(m[_k])
+		}
+		fmt.Fprintf(out, "#define %s %s\n", constant("git."+_k), _value)
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "#endif /* GITINFO_H */")
+} // c()
+
+// python generates a Python module defining the git information as
+// module constants, and the GITINFO dictionary of field names to values
+func python(out io.Writer, m map[string]string, when time.Time) {
+	fmt.Fprintln(out, comment(header(when), "#"))
+	fmt.Fprintln(out)
+	for _, _k := range keys(m) {
+		_value := literal(m[_k], utf16)
+		if _BOOLEANS[_k] {
+			_value = "False"
+			if _bool, _ := strconv.ParseBool(m[_k]); _bool {
+				_value = "True"
+			}
+		} else if _INTEGERS[_k] {
+			_value = integer(m[_k])
+		}
+		fmt.Fprintf(out, "%s = %s\n", constant(_k), _value)
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "GITINFO = {")
+	for _, _k := range keys(m) {
+		fmt.Fprintf(out, "    %s: %s,\n",
+			literal(_k, utf16), literal(m[_k], utf16),
+		)
+	}
+	fmt.Fprintln(out, "}")
+} // python()
+
+// javascript generates a JavaScript or TypeScript module exporting the git
+// information as constants, and the GITINFO object of field names to values
+func javascript(out io.Writer, m map[string]string, when time.Time) {
+	fmt.Fprintln(out, comment(header(when), "//"))
+	fmt.Fprintln(out)
+	for _, _k := range keys(m) {
+		_value := literal(m[_k], utf16)
+		if _BOOLEANS[_k] {
+			_bool, _ := strconv.ParseBool(m[_k])
+			_value = strconv.FormatBool(_bool)
+		} else if _INTEGERS[_k] {
+			_value = integer(m[_k])
+		}
+		fmt.Fprintf(out, "export const %s = %s;\n", constant(_k), _value)
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "export const GITINFO = {")
+	for _, _k := range keys(m) {
+		fmt.Fprintf(out, "  %s: %s,\n",
+			literal(_k, utf16), literal(m[_k], utf16),
+		)
+	}
+	fmt.Fprintln(out, "};")
+} // javascript()
+
+// rust generates a Rust module defining the git information as constants,
+// and the GITINFO slice of field names and values
+func rust(out io.Writer, m map[string]string, when time.Time) {
+	fmt.Fprintln(out, comment(header(when), "//"))
+	fmt.Fprintln(out)
+	for _, _k := range keys(m) {
+		_type, _value := "&str", literal(m[_k], braced)
+		if _BOOLEANS[_k] {
+			_bool, _ := strconv.ParseBool(m[_k])
+			_type, _value = "bool", strconv.FormatBool(_bool)
+		} else if _INTEGERS[_k] {
+			_type, _value = "u64", integer(m[_k])
+		}
+		fmt.Fprintf(out,
+			"pub const %s: %s = %s;\n", constant(_k), _type, _value,
+		)
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "pub const GITINFO: &[(&str, &str)] = &[")
+	for _, _k := range keys(m) {
+		fmt.Fprintf(out, "    (%s, %s),\n",
+			literal(_k, braced), literal(m[_k], braced),
+		)
+	}
+	fmt.Fprintln(out, "];")
+} // rust()
+
+// properties generates a Java properties file of the git information,
+// using the field names as keys
+func properties(out io.Writer, m map[string]string, when time.Time) {
+	fmt.Fprintln(out, comment(header(when), "#"))
+	for _, _k := range keys(m) {
+		// properties files are ISO-8859-1 encoded, with leading whitespace
+		// of values ignored
+		_value := escape(m[_k], func(r rune) string {
+			if r > unicode.MaxASCII || unicode.IsControl(r) {
+				return utf16(r)
+			}
+			return ""
+		})
+		if strings.HasPrefix(_value, " ") {
+			_value = `\` + _value
+		}
+		fmt.Fprintf(out, "%s=%s\n", key(_k), _value)
+	}
+} // properties()
+
+// key returns the given properties file key, escaping the separators and
+// whitespace that would otherwise terminate the key
+func key(name string) string {
+	return escape(name, func(r rune) string {
+		switch {
+		case r == ':' || r == '=' || r == '#' || r == '!' || r == ' ':
+			return `\` + string(r)
+		case r > unicode.MaxASCII || unicode.IsControl(r):
+			return utf16(r)
+		}
+		return ""
+	})
+} // key()
+
+// integer returns the integer literal for the given value, which is 0 if
+// the value is unknown
+func integer(value string) string {
+	_int, _ := strconv.Atoi(value)
+	return strconv.Itoa(_int)
+} // integer()
+
+// comment returns the header of generated Go files using the given comment
+// marker
+func comment(header, marker string) string {
+	_lines := strings.Split(strings.TrimSpace(header), "\n")
+	for _i, _line := range _lines {
+		_lines[_i] = marker + strings.TrimPrefix(_line, "//")
+	}
+
+	return strings.Join(_lines, "\n")
+} // comment()
+
+// constant returns the upper-case constant name for the given field name,
+// such as USER_NAME for "user.name"
+func constant(name string) string {
+	return strings.ToUpper(strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, name))
+} // constant()
+
+// literal returns the double-quoted string literal for the given value,
+// using control to escape control characters
+func literal(value string, control func(rune) string) string {
+	return `"` + escape(value, func(r rune) string {
+		if unicode.IsControl(r) {
+			return control(r)
+		}
+		return ""
+	}) + `"`
+} // literal()
+
+// escape returns the value with backslashes, double quotes, newlines,
+// carriage returns and tabs escaped, and any other character escaped as
+// returned by the given function, unless the function returns the empty
+// string
+func escape(value string, other func(rune) string) string {
+	_escaped := ""
+	for _, _r := range value {
+		switch _r {
+		case '\\':
+			_escaped += `\\`
+		case '"':
+			_escaped += `\"`
+		case '\n':
+			_escaped += `\n`
+		case '\r':
+			_escaped += `\r`
+		case '\t':
+			_escaped += `\t`
+		default:
+			if _string := other(_r); _string != "" {
+				_escaped += _string
+			} else {
+				_escaped += string(_r)
+			}
+		}
+	}
+
+	return _escaped
+} // escape()
+
+// octal returns the three digit octal escapes of the UTF-8 encoding of the
+// given character, as used by C
+func octal(r rune) string {
+	_escaped := ""
+	for _, _b := range []byte(string(r)) {
+		_escaped += fmt.Sprintf(`\%03o`, _b)
+	}
+	return _escaped
+} // octal()
+
+// utf16 returns the \uXXXX escape of the given character, as used by
+// Python, JavaScript and Java, with characters outside the basic
+// multilingual plane escaped as a UTF-16 surrogate pair for Java
+func utf16(r rune) string {
+	if r > 0xffff {
+		r -= 0x10000
+		return fmt.Sprintf(`\u%04x\u%04x`, 0xd800+(r>>10), 0xdc00+(r&0x3ff))
+	}
+	return fmt.Sprintf(`\u%04x`, r)
+} // utf16()
+
+// braced returns the \u{X} escape of the given character, as used by Rust
+func braced(r rune) string { return fmt.Sprintf(`\u{%x}`, r) }
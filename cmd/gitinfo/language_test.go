@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// the value used to test the escaping of each language
+//   - quotes, backslashes, control characters and non-ASCII characters,
+//     including characters outside the basic multilingual plane
+const _VALUE = "a\"b\\c\n\t\x01é😀"
+
+type _language struct {
+	language string
+	expected []string
+}
+
+var _LANGUAGE_TESTS = []_language{
+	{"c", []string{
+		`#define GIT_USER_NAME "a\"b\\c\n\t\001é😀"`,
+		`#define GIT_MODIFIED 1`,
+		`#define GIT_COUNT 42`,
+	}},
+	{"python", []string{
+		`USER_NAME = "a\"b\\c\n\t\u0001é😀"`,
+		`MODIFIED = True`,
+		`COUNT = 42`,
+		`    "user.name": "a\"b\\c\n\t\u0001é😀",`,
+	}},
+	{"js", []string{
+		`export const USER_NAME = "a\"b\\c\n\t\u0001é😀";`,
+		`export const MODIFIED = true;`,
+		`export const COUNT = 42;`,
+		`  "user.name": "a\"b\\c\n\t\u0001é😀",`,
+	}},
+	{"rust", []string{
+		`pub const USER_NAME: &str = "a\"b\\c\n\t\u{1}é😀";`,
+		`pub const MODIFIED: bool = true;`,
+		`pub const COUNT: u64 = 42;`,
+		`    ("user.name", "a\"b\\c\n\t\u{1}é😀"),`,
+	}},
+	{"java", []string{
+		`user.name=a\"b\\c\n\t\u0001\u00e9\ud83d\ude00`,
+		`modified=true`,
+		`count=42`,
+		`a\ b\:c\=d\#\!\u00e9=\ leading`,
+	}},
+}
+
+func TestLanguages(t *testing.T) {
+	_map := map[string]string{
+		"count":     "42",
+		"modified":  "true",
+		"user.name": _VALUE,
+	}
+	_when := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, _test := range _LANGUAGE_TESTS {
+		_m := _map
+		if _test.language == "java" {
+			_m = map[string]string{"a b:c=d#!é": " leading"}
+			for _k, _v := range _map {
+				_m[_k] = _v
+			}
+		}
+
+		_buffer := new(bytes.Buffer)
+		_LANGUAGES[_test.language](_buffer, _m, _when)
+		_lines := strings.Split(_buffer.String(), "\n")
+		for _, _expected := range _test.expected {
+			_found := false
+			for _, _line := range _lines {
+				if _line == _expected {
+					_found = true
+					break
+				}
+			}
+			if !_found {
+				t.Fatalf(
+					"%s: expected line %q; got\n%s",
+					_test.language, _expected, _buffer.String(),
+				)
+			}
+		}
+	}
+} // TestLanguages()
+
+func TestInteger(t *testing.T) {
+	for _value, _expected := range map[string]string{
+		"42":   "42",
+		"007":  "7",
+		"":     "0",
+		"many": "0",
+	} {
+		if _integer := integer(_value); _integer != _expected {
+			t.Fatalf(
+				"%q: unexpected integer; expected %q, got %q",
+				_value, _expected, _integer,
+			)
+		}
+	}
+} // TestInteger()
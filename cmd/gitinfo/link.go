@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/denormal/go-gitinfo"
+	"github.com/denormal/go-gitinfo/links"
+)
+
+// link outputs the web URL of the given file, optionally referencing the
+// given line, as of the HEAD commit of the file's working copy:
+//
+//	gitinfo link [-ref ref] <file>[:line]
+func link(out io.Writer, args []string) {
+	_flags := flag.NewFlagSet("link", flag.ExitOnError)
+	_ref := _flags.String("ref", "",
+		"Link to the file as of `ref` (e.g. a branch name) instead of the "+
+			"HEAD commit.",
+	)
+	_flags.Parse(args)
+	if _flags.NArg() != 1 {
+		fail(1, "%s: link: expected <file>[:line]\n", exe())
+	}
+
+	// have we been given a line number?
+	_file, _line := _flags.Arg(0), 0
+	if _i := strings.LastIndex(_file, ":"); _i > 0 {
+		_n, _err := strconv.Atoi(_file[_i+1:])
+		if _err == nil && _n > 0 {
+			_file, _line = _file[:_i], _n
+		}
+	}
+
+	// determine the path of the file relative to the working copy root
+	_path, _err := filepath.Abs(_file)
+	if _err == nil {
+		_path, _err = filepath.EvalSymlinks(_path)
+	}
+	if _err != nil {
+		fail(2, "%s: link: error: %s\n", exe(), _err.Error())
+	}
+	_info, _err := gitinfo.NewWithPath(_path)
+	if _err != nil {
+		fail(2, "%s: link: error: %s\n", exe(), _err.Error())
+	} else if _info.Root() == "" {
+		fail(2, "%s: link: error: %s\n",
+			exe(), gitinfo.MissingWorkingCopyError.Error(),
+		)
+	}
+	_relative, _err := filepath.Rel(_info.Root(), _path)
+	if _err != nil || strings.HasPrefix(_relative, ".."+string(os.PathSeparator)) {
+		fail(2, "%s: link: %s is outside the working copy\n", exe(), _file)
+	}
+
+	// link to the HEAD commit, unless we have been given a ref
+	if *_ref == "" {
+		_commit, _err := _info.Commit()
+		if _err != nil {
+			fail(2, "%s: link: error: %s\n", exe(), _err.Error())
+		} else if _commit == nil {
+			fail(2, "%s: link: error: no commits\n", exe())
+		}
+		*_ref = _commit.String()
+	}
+
+	// construct the URL
+	_repository, _err := links.NewWithConfig(_info.Config())
+	if _err != nil {
+		fail(2, "%s: link: error: %s\n", exe(), _err.Error())
+	}
+	fmt.Fprintln(out,
+		_repository.File(*_ref, filepath.ToSlash(_relative), _line),
+	)
+} // link()
+
+func init() {
+	register("link", "link [-ref ref] <file>[:line]", link)
+} // init()
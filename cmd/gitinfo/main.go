@@ -1,7 +1,9 @@
 package main
 
 import (
+	"bytes"
 	"flag"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
@@ -17,19 +19,32 @@ const (
 )
 
 type options struct {
+	changed *bool   // only write the output file if its content changes
+	commit  *bool   // use the HEAD commit time in generated files
 	env     *bool   // environment only: editor,user.*,path,root,version
 	fields  *string // explicit list of fields
+	first   *bool   // count first-parent commits only
+	format  *string // output the git information in this format
 	h       *bool   // short help
 	help    *bool   // full help
+	lang    *string // generate the git information for this language
+	noci    *bool   // ignore the CI environment
+	nopaths *bool   // omit the path and root fields with -X
 	output  *string // output to this file
+	patch   *bool   // include the patch of uncommitted changes with -X
 	r       *bool   // runtime update of the package symbol
 	runtime *bool   //		- as with 'r'
 	s       *bool   // short output without field names
 	short   *bool   //		- as with 's'
+	signed  *bool   // fail unless HEAD has a valid signature
+	since   *bool   // count commits since the most recent tag only
 	src     *bool   // source information only: commit,branch,modified
+	sprefix *string // the prefix of stable keys with -format bazel
+	stand   *bool   // generate code without a dependency on go-gitinfo
 	symbol  *string // the package symbol
 	v       *bool   // output short version information
 	version *bool   // output detailed version information
+	vprefix *string // the prefix of volatile keys with -format bazel
 }
 
 var opt *options
@@ -48,17 +63,41 @@ func main() {
 	}
 
 	// are we outputting to a file or stdout?
+	//		- should we only write the file if its content changes?
 	var (
-		_out = os.Stdout
-		_err error
+		_out   io.Writer = os.Stdout
+		_flush           = func() {}
+		_err   error
 	)
 	if *opt.output != "" {
 		_path := filepath.Join(strings.Split(*opt.output, "/")...)
-		_out, _err = os.Create(_path)
-		if _err != nil {
-			fail(1, "%s: error: %s: %s\n", exe(), _path, _err.Error())
+		if *opt.changed {
+			_buffer := new(bytes.Buffer)
+			_out, _flush = _buffer, func() {
+				_err := update(_path, _buffer.Bytes())
+				if _err != nil {
+					fail(1, "%s: error: %s: %s\n", exe(), _path, _err.Error())
+				}
+			}
+		} else {
+			_file, _err := os.Create(_path)
+			if _err != nil {
+				fail(1, "%s: error: %s: %s\n", exe(), _path, _err.Error())
+			}
+			defer _file.Close()
+			_out = _file
+		}
+	}
+
+	// have we been given a command?
+	//		- commands take precedence over paths of the same name
+	if len(flag.Args()) > 0 {
+		_command, _ok := commands[flag.Arg(0)]
+		if _ok {
+			_command.run(_out, flag.Args()[1:])
+			_flush()
+			ok()
 		}
-		defer _out.Close()
 	}
 
 	// should we only output certain fields?
@@ -101,10 +140,56 @@ func main() {
 		_pkg, _var = _parts[0], _parts[1]
 	}
 
+	// standalone code cannot check the git information at runtime
+	if *opt.stand && (*opt.r || *opt.runtime) {
+		fail(1, "%s: -runtime cannot be used with -standalone\n", exe())
+	}
+
+	// are we generating the git information for another language?
+	//		- the symbol and its options only apply to Go
+	_lang := *opt.lang
+	if _lang == "go" {
+		_lang = ""
+	} else if _lang != "" {
+		if _, _ok := _LANGUAGES[_lang]; !_ok {
+			fail(1,
+				"%s: unknown language %q; expected one of %s\n",
+				exe(), _lang, strings.Join(languages(), ", "),
+			)
+		} else if _pkg != "" {
+			fail(1, "%s: -X cannot be used with -lang %s\n", exe(), _lang)
+		}
+	}
+
+	// are we outputting the git information in an alternative format?
+	//		- formats determine their own fields
+	var _format func(io.Writer, gitinfo.GitInfo) error
+	if *opt.format != "" {
+		_fn, _ok := _FORMATS[*opt.format]
+		if !_ok {
+			fail(1,
+				"%s: unknown format %q; expected one of %s\n",
+				exe(), *opt.format, strings.Join(formats(), ", "),
+			)
+		} else if _pkg != "" || _lang != "" {
+			fail(1, "%s: -format cannot be used with -X or -lang\n", exe())
+		}
+		_format = _fn
+
+		// are the Bazel key prefixes valid?
+		if *opt.format == "bazel" {
+			_err := prefixes(*opt.sprefix, *opt.vprefix)
+			if _err != nil {
+				fail(1, "%s: %s\n", exe(), _err.Error())
+			}
+		}
+	}
+
 	// have we been given a path?
 	//		- attempt to load the gitinfo for this path or the current path
 	var (
-		_info gitinfo.GitInfo
+		_info    gitinfo.GitInfo
+		_exclude []string
 	)
 	if len(flag.Args()) == 0 {
 		_info, _err = gitinfo.New()
@@ -112,23 +197,89 @@ func main() {
 		_info, _err = gitinfo.NewWithPath(flag.Arg(0))
 	}
 
+	// the output file should not cause the working copy to be modified
+	//		- recognise the CI environment if it is building this working
+	//		  copy, unless -no-ci is given
+	if _err == nil {
+		if *opt.output != "" {
+			_exclude = exclude(_info, *opt.output)
+		}
+		_info, _err = load(
+			_info.Path(), gitinfo.ModifiedOptions{Exclude: _exclude},
+			!*opt.noci,
+		)
+	}
+
 	// did we encounter an error?
 	if _err != nil {
 		fail(2, "%s: error: %s\n", exe(), _err.Error())
 	} else if _info != nil {
+		// should we ensure HEAD is signed?
+		if *opt.signed {
+			signed(_info)
+		}
+
+		// should we output an alternative format?
+		if _format != nil {
+			_err := _format(_out, _info)
+			if _err != nil {
+				fail(3, "%s: error: %s\n", exe(), _err.Error())
+			}
+			_flush()
+			ok()
+		}
+
 		_map, _err := build(_info, _f)
+		if _err == nil {
+			_err = count(_info, _map)
+		}
 		if _err != nil {
 			fail(3, "%s: error: %s\n", exe(), _err.Error())
-		} else if _pkg == "" {
+		} else if _pkg == "" && _lang == "" {
 			// do we want a short or long display?
 			//		- i.e. just the values, or key = value?
-			display(_out, _map, *opt.s || *opt.short, _f)
+			//		- warn of shallow clones in the long display
+			_short := *opt.s || *opt.short
+			if !_short {
+				shallow(_info)
+			}
+			display(_out, _map, _short, _f)
 		} else {
-			generate(_out, _map, _pkg, _var, *opt.r || *opt.runtime)
+			// should we include the patch of the uncommitted changes?
+			if *opt.patch {
+				_patch, _err := _info.Patch()
+				if _err != nil {
+					fail(3, "%s: error: %s\n", exe(), _err.Error())
+				}
+				_map[gitinfo.MODIFIED_PATCH] = _patch.Encode()
+			}
+
+			// should we omit the absolute paths of this system?
+			if *opt.nopaths {
+				delete(_map, gitinfo.PATH)
+				delete(_map, gitinfo.ROOT)
+			}
+
+			// determine the timestamp of the generated file
+			_when, _err := timestamp(_info, *opt.commit)
+			if _err != nil {
+				fail(3, "%s: error: %s\n", exe(), _err.Error())
+			}
+			if _lang != "" {
+				_LANGUAGES[_lang](_out, _map, _when)
+			} else if *opt.stand {
+				standalone(_out, _map, _pkg, _var, _when)
+			} else {
+				generate(
+					_out, _map, _pkg, _var,
+					*opt.r || *opt.runtime, _exclude, _when,
+				)
+			}
 		}
 	}
 
 	// everything is OK
+	_flush()
 	ok()
 } // main()
 
@@ -174,10 +325,63 @@ func init() {
 		s:     _b("s", ""),
 		short: _b("short", "Short display; only output field values."),
 
+		changed: _b("if-changed",
+			"When used with -o, leave the output file untouched if its "+
+				"content, ignoring\n"+
+				"\tthe header of generated files, is unchanged.",
+		),
+		commit: _b("commit-time",
+			"When used with -X or -lang, record the time of the HEAD "+
+				"commit in the\n"+
+				"\tgenerated file, instead of $SOURCE_DATE_EPOCH or the "+
+				"current time.",
+		),
 		env: _b("env",
 			"Environment information only; equivalent to\n"+
 				"\t-f editor,git,path,root,user.*.",
 		),
+		first: _b("first-parent",
+			"Count only the first-parent commits of HEAD for the count "+
+				"field.",
+		),
+		noci: _b("no-ci",
+			"Ignore the CI environment; by default, the branch and ci.* "+
+				"fields are\n"+
+				"\ttaken from the CI environment building the working copy.",
+		),
+		nopaths: _b("no-paths",
+			"When used with -X or -lang, omit the path and root fields, "+
+				"which are\n"+
+				"\tspecific to this system.",
+		),
+		patch: _b("patch",
+			"When used with -X or -lang, embed the patch of uncommitted "+
+				"changes,\n"+
+				"\tincluding untracked files, for use with the reproduce "+
+				"command.",
+		),
+		since: _b("since-tag",
+			"Count only the commits since the most recent tag for the "+
+				"count field.",
+		),
+		signed: _b("signed",
+			"Exit with an error if HEAD does not have a valid signature.",
+		),
+		stand: _b("standalone",
+			"When used with -X, generate typed constants and a "+
+				"self-contained struct,\n"+
+				"\twithout a dependency on go-gitinfo, instead of a "+
+				"gitinfo.GitInfo.",
+		),
+		sprefix: flag.String("stable-prefix", "STABLE_GIT_",
+			"When used with -format bazel, the `prefix` of the stable "+
+				"keys, which must\n"+
+				"\tstart with STABLE_.",
+		),
+		vprefix: flag.String("volatile-prefix", "GIT_",
+			"When used with -format bazel, the `prefix` of the volatile "+
+				"keys.",
+		),
 		src: _b("src",
 			"Source information only; equivalent to "+
 				"-f branch,commit,modified.",
@@ -187,6 +391,15 @@ func init() {
 			"Output just the given `fields` (comma-separated); choose from:\n"+
 				strings.Join(_text, "\n"),
 		),
+		format: _s("format",
+			"Output the git information in the given `format`; one of\n"+
+				"\t"+strings.Join(formats(), ", ")+".",
+		),
+		lang: _s("lang",
+			"Output the git information as source for the given `language`; "+
+				"one of\n"+
+				"\t"+strings.Join(languages(), ", ")+".",
+		),
 		output: _s("o", "Output to `path` instead of STDOUT."),
 		symbol: _s("X",
 			"Output the git information to the package variable `pkg.var` "+
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/denormal/go-gitinfo/semver"
+)
+
+// nextversion outputs the suggested next semantic version for the working
+// copy at the given path (or the current directory), failing for shallow
+// clones missing the commits since the latest version tag:
+//
+//	gitinfo next-version [path]
+func nextversion(out io.Writer, args []string) {
+	_path := ""
+	if len(args) > 1 {
+		fail(1, "%s: next-version: too many arguments\n", exe())
+	} else if len(args) == 1 {
+		_path = args[0]
+	}
+
+	// determine the next version
+	_version, _err := semver.Next(_path)
+	if _, _ok := _err.(*semver.ShallowCloneError); _ok {
+		//		- identify the shallow clone
+		if _path == "" {
+			_path = "."
+		}
+		fail(2, "%s: next-version: error: %s: %s\n",
+			exe(), _path, _err.Error(),
+		)
+	} else if _err != nil {
+		fail(2, "%s: next-version: error: %s\n", exe(), _err.Error())
+	}
+
+	fmt.Fprintln(out, _version.String())
+} // nextversion()
+
+func init() {
+	register("next-version", "next-version [path]", nextversion)
+} // init()
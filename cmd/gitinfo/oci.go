@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/denormal/go-gitinfo"
+	"github.com/denormal/go-gitinfo/links"
+	"github.com/denormal/go-gittools"
+)
+
+// the prefix of the OCI image annotations
+const _OCI = "org.opencontainers.image."
+
+// ociLabels outputs the OCI image annotations of the git information as
+// docker build --label arguments
+func ociLabels(out io.Writer, gi gitinfo.GitInfo) error {
+	_oci, _err := oci(gi)
+	if _err != nil {
+		return _err
+	}
+
+	_args := make([]string, 0, len(_oci))
+	for _, _k := range keys(_oci) {
+		_args = append(_args, "--label", quote(_OCI+_k+"="+_oci[_k]))
+	}
+	fmt.Fprintln(out, strings.Join(_args, " "))
+
+	return nil
+} // ociLabels()
+
+// ociDockerfile outputs the OCI image annotations of the git information as
+// a Dockerfile LABEL instruction
+func ociDockerfile(out io.Writer, gi gitinfo.GitInfo) error {
+	_oci, _err := oci(gi)
+	if _err != nil {
+		return _err
+	} else if len(_oci) == 0 {
+		return nil
+	}
+
+	// Dockerfile label values are expanded, so we must escape variables
+	_labels := make([]string, 0, len(_oci))
+	for _, _k := range keys(_oci) {
+		_value := escape(_oci[_k], func(r rune) string {
+			if r == '$' {
+				return `\$`
+			}
+			return ""
+		})
+		_labels = append(_labels, fmt.Sprintf("%s%s=\"%s\"", _OCI, _k, _value))
+	}
+	fmt.Fprintln(out, "LABEL "+strings.Join(_labels, " \\\n      "))
+
+	return nil
+} // ociDockerfile()
+
+// ociBuildArgs outputs the OCI image annotations of the git information as
+// docker build --build-arg arguments, named after the annotations (e.g.
+// REVISION for org.opencontainers.image.revision)
+func ociBuildArgs(out io.Writer, gi gitinfo.GitInfo) error {
+	_oci, _err := oci(gi)
+	if _err != nil {
+		return _err
+	}
+
+	_args := make([]string, 0, len(_oci))
+	for _, _k := range keys(_oci) {
+		_args = append(_args, "--build-arg", quote(constant(_k)+"="+_oci[_k]))
+	}
+	fmt.Fprintln(out, strings.Join(_args, " "))
+
+	return nil
+} // ociBuildArgs()
+
+// oci returns the OCI image annotations of the git information, without the
+// org.opencontainers.image. prefix:
+//
+//	created   the time of the HEAD commit
+//	revision  the HEAD commit
+//	source    the web URL of the origin remote
+//	version   the Go pseudo-version of the HEAD commit
+//
+// Annotations are omitted if their values cannot be determined, such as
+// the version of shallow clones.
+func oci(gi gitinfo.GitInfo) (map[string]string, error) {
+	_oci := make(map[string]string)
+
+	// do we have a commit?
+	_commit, _err := gi.Commit()
+	if _err != nil {
+		return nil, _err
+	} else if _commit != nil {
+		_oci["revision"] = _commit.String()
+
+		_time, _err := _commit.Time()
+		if _err != nil {
+			return nil, _err
+		}
+		_oci["created"] = _time.UTC().Format(time.RFC3339)
+
+		//		- shallow clones may not have the history for the version
+		_version, _err := gi.PseudoVersion()
+		if _err == nil {
+			_oci["version"] = _version
+		} else if _, _ok := _err.(*gitinfo.ShallowCloneError); !_ok {
+			return nil, _err
+		}
+	}
+
+	// do we have a remote?
+	_source, _err := source(gi)
+	if _err != nil {
+		return nil, _err
+	} else if _source != "" {
+		_oci["source"] = _source
+	}
+
+	return _oci, nil
+} // oci()
+
+// source returns the web URL of the origin remote of the working copy, or
+// the remote URL without credentials if its hosting provider is not known,
+// or the empty string if the working copy has no origin remote
+func source(gi gitinfo.GitInfo) (string, error) {
+	if gi.Root() == "" {
+		return "", nil
+	}
+
+	// is the remote hosted by a known provider?
+	_repository, _err := links.NewWithRemote(gi.Config(), "origin")
+	if _err == nil {
+		return _repository.URL(), nil
+	} else if _err == links.MissingRemoteError {
+		return "", nil
+	}
+
+	// ensure we don't publish any credentials of the remote URL
+	_output, _err := gittools.RunInPath(
+		gi.Root(), "remote", "get-url", "origin",
+	)
+	if _err != nil {
+		return "", nil
+	}
+	_remote := strings.TrimSpace(string(_output))
+	_url, _err := url.Parse(_remote)
+	if _err == nil && _url.User != nil {
+		_url.User = nil
+		_remote = _url.String()
+	}
+
+	return _remote, nil
+} // source()
+
+// quote returns the given shell argument, single-quoted if required
+func quote(arg string) string {
+	_safe := strings.IndexFunc(arg, func(r rune) bool {
+		return !strings.ContainsRune(
+			"abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"+
+				"0123456789-_./:=+@%,", r,
+		)
+	}) < 0
+	if _safe && arg != "" {
+		return arg
+	}
+
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+} // quote()
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestQuote(t *testing.T) {
+	for _arg, _expected := range map[string]string{
+		"":                          "''",
+		"v1.0.0":                    "v1.0.0",
+		"key=https://example.com/x": "key=https://example.com/x",
+		"two words":                 "'two words'",
+		"it's":                      `'it'\''s'`,
+		"'":                         `''\'''`,
+		"$HOME":                     "'$HOME'",
+		"a\nb":                      "'a\nb'",
+	} {
+		if _quoted := quote(_arg); _quoted != _expected {
+			t.Fatalf(
+				"%q: unexpected quote; expected %q, got %q",
+				_arg, _expected, _quoted,
+			)
+		}
+	}
+} // TestQuote()
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/denormal/go-gitinfo"
+	"github.com/denormal/go-gittools"
+)
+
+// reproduce recreates the source of a build generated with -patch, creating
+// a worktree of the current working copy at the recorded commit and applying
+// the recorded patch. The build information is read either from a compiled
+// binary, or from a JSON object of the git information fields:
+//
+//	gitinfo reproduce [-dir path] <binary-or-json>
+func reproduce(out io.Writer, args []string) {
+	_flags := flag.NewFlagSet("reproduce", flag.ExitOnError)
+	_dir := _flags.String("dir", "",
+		"Create the worktree at `path`, instead of alongside the working "+
+			"copy.",
+	)
+	_flags.Parse(args)
+	if _flags.NArg() != 1 {
+		fail(1, "%s: reproduce: expected <binary-or-json>\n", exe())
+	}
+
+	// extract the patch
+	_bytes, _err := ioutil.ReadFile(_flags.Arg(0))
+	if _err != nil {
+		fail(2, "%s: reproduce: error: %s\n", exe(), _err.Error())
+	}
+	var (
+		_map   map[string]string
+		_patch gitinfo.Patch
+	)
+	if json.Unmarshal(_bytes, &_map) == nil {
+		_patch, _err = gitinfo.Build(_map).Patch()
+	} else {
+		_patch, _err = gitinfo.FindPatch(_bytes)
+	}
+	if _err != nil {
+		fail(2, "%s: reproduce: error: %s: %s\n",
+			exe(), _flags.Arg(0), _err.Error(),
+		)
+	}
+
+	// create the worktree at the recorded commit
+	_info, _err := gitinfo.New()
+	if _err != nil {
+		fail(2, "%s: reproduce: error: %s\n", exe(), _err.Error())
+	} else if _info.Root() == "" {
+		fail(2, "%s: reproduce: error: %s\n",
+			exe(), gitinfo.MissingWorkingCopyError.Error(),
+		)
+	}
+	//		- the commit must not be mistaken for a git option
+	_commit := _patch.Commit()
+	if !object(_commit) {
+		fail(2, "%s: reproduce: error: %s: invalid commit %q\n",
+			exe(), _flags.Arg(0), _commit,
+		)
+	}
+	if *_dir == "" {
+		_prefix := _commit
+		if len(_prefix) > BUILD {
+			_prefix = _prefix[:BUILD]
+		}
+		*_dir = _info.Root() + "-" + _prefix
+	}
+	*_dir, _err = filepath.Abs(*_dir)
+	if _err != nil {
+		fail(2, "%s: reproduce: error: %s\n", exe(), _err.Error())
+	}
+	_, _err = gittools.RunInPath(
+		_info.Root(), "worktree", "add", "--detach", *_dir, _commit,
+	)
+	if _err != nil {
+		fail(3, "%s: reproduce: error: %s\n", exe(), _err.Error())
+	}
+
+	// apply the patch
+	_err = _patch.Apply(*_dir)
+	if _err != nil {
+		fail(3, "%s: reproduce: error: %s\n", exe(), _err.Error())
+	}
+
+	fmt.Fprintln(out, *_dir)
+} // reproduce()
+
+func init() {
+	register("reproduce", "reproduce [-dir path] <binary-or-json>", reproduce)
+} // init()
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/denormal/go-gitinfo"
+)
+
+// shallow warns if the working copy is a shallow clone, since fields that
+// depend on the commit history, such as the commit count, are unavailable
+func shallow(gi gitinfo.GitInfo) {
+	_shallow, _err := gi.IsShallow()
+	if _err == nil && _shallow {
+		fmt.Fprintf(os.Stderr,
+			"%s: warning: %s is a shallow clone; "+
+				"history-dependent fields are unavailable\n",
+			exe(), gi.Root(),
+		)
+	}
+} // shallow()
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"github.com/denormal/go-gitinfo"
+)
+
+// signed ensures the HEAD commit of the given working copy has a valid
+// signature, failing with a description of the signature status if not
+func signed(gi gitinfo.GitInfo) {
+	_commit, _err := gi.Commit()
+	if _err != nil {
+		fail(2, "%s: error: %s\n", exe(), _err.Error())
+	} else if _commit == nil || _commit.String() == "" {
+		fail(4, "%s: error: HEAD is not signed: no commit\n", exe())
+	}
+
+	// does HEAD have a valid signature?
+	_signature, _err := _commit.Signature()
+	if _err != nil {
+		fail(2, "%s: error: %s\n", exe(), _err.Error())
+	} else if !_signature.Valid() {
+		fail(4,
+			"%s: error: HEAD is not signed: %s\n",
+			exe(), _signature.Status().String(),
+		)
+	}
+} // signed()
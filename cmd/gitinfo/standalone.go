@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"go/format"
+
+	"github.com/denormal/go-gitinfo"
+)
+
+var (
+	// the boolean fields of standalone generated code
+	//		- all other fields are strings
+	_BOOLEANS = map[string]bool{
+		gitinfo.COMMIT_SIGNED: true,
+		gitinfo.MODIFIED:      true,
+	}
+
+	// the integer fields of standalone generated code
+	_INTEGERS = map[string]bool{
+		gitinfo.COUNT: true,
+	}
+
+	// the initialisms of field names in standalone generated code
+	_INITIALISMS = map[string]string{
+		"ci":  "CI",
+		"pr":  "PR",
+		"url": "URL",
+	}
+)
+
+// standalone generates the git information as typed constants of the
+// package pkg, prefixed by the name v, together with the variable v of a
+// self-contained struct type providing String() and Map() methods. Unlike
+// generate(), the generated code does not depend on go-gitinfo.
+func standalone(
+	out io.Writer,
+	m map[string]string,
+	pkg, v string,
+	when time.Time,
+) {
+	// the struct type follows the export of the variable name
+	_type := v + "Info"
+
+	// generate the constants, struct fields and their values, and the
+	// map entries
+	var (
+		_consts  []string
+		_fields  []string
+		_values  []string
+		_entries []string
+		_strconv bool
+	)
+	for _, _k := range keys(m) {
+		_field := field(_k)
+		_const := v + _field
+		_value := fmt.Sprintf("%q", m[_k])
+		_kind := "string"
+		_entry := "g." + _field
+		if _BOOLEANS[_k] {
+			_bool, _ := strconv.ParseBool(m[_k])
+			_value = strconv.FormatBool(_bool)
+			_kind = "bool"
+			_entry = "strconv.FormatBool(" + _entry + ")"
+			_strconv = true
+		} else if _INTEGERS[_k] {
+			_value = integer(m[_k])
+			_kind = "int"
+			_entry = "strconv.Itoa(" + _entry + ")"
+			_strconv = true
+		}
+
+		_consts = append(_consts, _const+" "+_kind+" = "+_value)
+		_fields = append(_fields, _field+" "+_kind)
+		_values = append(_values, _field+": "+_const+",")
+		_entries = append(_entries, fmt.Sprintf("%q: %s,", _k, _entry))
+	}
+
+	// the string representation is the branch and commit, marking
+	// modified builds
+	_string := []string{}
+	for _, _k := range []string{gitinfo.BRANCH, gitinfo.COMMIT} {
+		if _, _ok := m[_k]; _ok {
+			_string = append(_string, "g."+field(_k))
+		}
+	}
+	if len(_string) == 0 {
+		_string = append(_string, `""`)
+	}
+	_modified := ""
+	if _, _ok := m[gitinfo.MODIFIED]; _ok {
+		_modified = fmt.Sprintf(
+			"\nif g.%s {\n_s = _s + \"+\"\n}", field(gitinfo.MODIFIED),
+		)
+	}
+
+	// do we need to import strconv?
+	_import := `"strings"`
+	if _strconv {
+		_import = `"strconv"` + "\n" + _import
+	}
+
+	// generate the package definition
+	_src := fmt.Sprintf(
+		`%s
+package %s
+
+import (
+    %s
+)
+
+// the git information of this build
+const (
+    %s
+)
+
+// %s describes the git information of this build.
+type %s struct {
+    %s
+}
+
+var %s = %s{
+    %s
+}
+
+// String returns the branch and commit of the build, with "+" appended for
+// builds from modified working copies.
+func (g %s) String() string {
+    _s := strings.TrimSpace(%s)%s
+
+    return _s
+}
+
+// Map returns the git information as a map of field names to values.
+func (g %s) Map() map[string]string {
+    return map[string]string{
+        %s
+    }
+}`,
+		header(when), pkg, _import,
+		strings.Join(_consts, "\n"),
+		_type, _type, strings.Join(_fields, "\n"),
+		v, _type, strings.Join(_values, "\n"),
+		_type, strings.Join(_string, ` + " " + `), _modified,
+		_type, strings.Join(_entries, "\n"),
+	)
+
+	// apply standard formatting
+	_bytes, _err := format.Source([]byte(_src))
+	if _err != nil {
+		panic(_err)
+	}
+
+	fmt.Fprint(out, string(_bytes))
+} // standalone()
+
+// field returns the exported Go identifier for the given field name, such
+// as "UserName" for "user.name"
+func field(name string) string {
+	_parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _i, _part := range _parts {
+		if _initialism, _ok := _INITIALISMS[_part]; _ok {
+			_parts[_i] = _initialism
+		} else {
+			_parts[_i] = strings.ToUpper(_part[:1]) + _part[1:]
+		}
+	}
+
+	return strings.Join(_parts, "")
+} // field()
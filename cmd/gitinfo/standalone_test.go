@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/denormal/go-gitinfo"
+)
+
+// the program used to report the standalone git information
+const _MAIN = `package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+func main() {
+	_bytes, _ := json.Marshal(git.Map())
+	fmt.Println(git.String())
+	fmt.Println(string(_bytes))
+}
+`
+
+func TestStandalone(t *testing.T) {
+	// if we don't have the go tool installed, then skip this test
+	_go, _err := exec.LookPath("go")
+	if _err != nil {
+		t.Skip("go not installed")
+	}
+
+	_map := gitinfo.Build(map[string]string{
+		gitinfo.BRANCH:    "main",
+		gitinfo.COMMIT:    "0123456789abcdef0123456789abcdef01234567",
+		gitinfo.COUNT:     "42",
+		gitinfo.MODIFIED:  "true",
+		gitinfo.USER_NAME: _VALUE,
+	}).Map()
+	_buffer := new(bytes.Buffer)
+	standalone(_buffer, _map, "main", "git", time.Now())
+
+	// are the constants typed?
+	_file, _err := parser.ParseFile(
+		token.NewFileSet(), "git.go", _buffer.Bytes(), 0,
+	)
+	if _err != nil {
+		t.Fatalf("unable to parse generated code: %s", _err.Error())
+	}
+	for _, _decl := range _file.Decls {
+		_gen, _ok := _decl.(*ast.GenDecl)
+		if !_ok || _gen.Tok != token.CONST {
+			continue
+		}
+		for _, _spec := range _gen.Specs {
+			_value := _spec.(*ast.ValueSpec)
+			if _value.Type == nil {
+				t.Fatalf("untyped constant %s", _value.Names[0].Name)
+			}
+		}
+	}
+
+	// compile and run the generated code
+	_dir, _err := ioutil.TempDir("", "")
+	if _err != nil {
+		t.Fatalf("unable to create temporary directory: %s", _err.Error())
+	}
+	defer os.RemoveAll(_dir)
+	for _name, _content := range map[string][]byte{
+		"go.mod":  []byte("module standalone\n"),
+		"git.go":  _buffer.Bytes(),
+		"main.go": []byte(_MAIN),
+	} {
+		_err = ioutil.WriteFile(filepath.Join(_dir, _name), _content, 0644)
+		if _err != nil {
+			t.Fatalf("unable to write %s: %s", _name, _err.Error())
+		}
+	}
+	_cmd := exec.Command(_go, "run", ".")
+	_cmd.Dir = _dir
+	_cmd.Env = append(os.Environ(), "GO111MODULE=on", "GOFLAGS=-mod=mod")
+	_output, _err := _cmd.CombinedOutput()
+	if _err != nil {
+		t.Fatalf("unable to run generated code: %s\n%s", _err.Error(), _output)
+	}
+	_lines := strings.SplitN(string(_output), "\n", 2)
+	if len(_lines) != 2 {
+		t.Fatalf("unexpected output %q", _output)
+	}
+
+	// do String() and Map() report the build information?
+	_string := "main 0123456789abcdef0123456789abcdef01234567+"
+	if _lines[0] != _string {
+		t.Fatalf(
+			"unexpected String(); expected %q, got %q", _string, _lines[0],
+		)
+	}
+	_got := make(map[string]string)
+	_err = json.Unmarshal([]byte(_lines[1]), &_got)
+	if _err != nil {
+		t.Fatalf("unable to decode Map(): %s", _err.Error())
+	} else if len(_got) != len(_map) {
+		t.Fatalf("unexpected Map(); expected %v, got %v", _map, _got)
+	}
+	for _k, _v := range _map {
+		if _got[_k] != _v {
+			t.Fatalf(
+				"unexpected value for %q; expected %q, got %q", _k, _v, _got[_k],
+			)
+		}
+	}
+} // TestStandalone()
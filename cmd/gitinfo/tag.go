@@ -28,11 +28,16 @@ func tag(long bool) string {
 			}
 
 			// is this checkout modified?
+			//		- distinguish modified builds by the dirty hash, if known
 			if len(_strings) > 1 {
 				_modified, _ := _git.Modified()
 				if _modified {
 					_last := len(_strings) - 1
 					_strings[_last] = _strings[_last] + "+"
+					_hash, _ := _git.DirtyHash()
+					if len(_hash) >= BUILD {
+						_strings[_last] = _strings[_last] + _hash[:BUILD]
+					}
 				}
 			}
 
@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+
+	"github.com/denormal/go-gitinfo"
 )
 
 func exe() string   { return filepath.Base(os.Args[0]) }
@@ -19,13 +22,44 @@ func fail(code int, format string, args ...interface{}) {
 func usage(long bool) {
 	fmt.Printf("%s [options]\n", exe())
 	fmt.Printf("%s [options] <path>\n", exe())
+	fmt.Printf("%s [options] <command> [arguments]\n", exe())
 
 	// do we need to display long usage?
 	if long {
 		fmt.Println()
 		flag.PrintDefaults()
+
+		// display the supported commands
+		fmt.Println()
+		fmt.Println("Commands:")
+		for _, _name := range names() {
+			fmt.Printf("  %s [options] %s\n", exe(), commands[_name].usage)
+		}
 	}
 
 	// we are done
 	ok()
 } // usage()
+
+// object returns true if s is a full hexadecimal git object name, of either
+// a SHA-1 or a SHA-256 repository
+func object(s string) bool {
+	if len(s) != 40 && len(s) != 64 {
+		return false
+	}
+
+	return strings.Trim(strings.ToLower(s), "0123456789abcdef") == ""
+} // object()
+
+// load returns the GitInfo of the working copy at path with the given
+// options, recognising the CI environment building the working copy unless
+// ci is false
+func load(path string, opts gitinfo.ModifiedOptions, ci bool) (
+	gitinfo.GitInfo, error,
+) {
+	if !ci {
+		return gitinfo.NewWithOptions(path, opts)
+	}
+
+	return gitinfo.NewWithCI(path, opts)
+} // load()
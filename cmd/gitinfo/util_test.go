@@ -0,0 +1,85 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/denormal/go-gitinfo"
+	"github.com/denormal/go-gittools"
+)
+
+func TestObject(t *testing.T) {
+	_sha1 := "0123456789abcdef0123456789ABCDEF01234567"
+	for _s, _expected := range map[string]bool{
+		_sha1:                          true,
+		strings.Repeat("a", 64):        true,
+		"":                             false,
+		_sha1[:39]:                     false,
+		_sha1 + "0":                    false,
+		"--output=/tmp/x" + _sha1[15:]: false,
+		"g" + _sha1[1:]:                false,
+		"HEAD":                         false,
+	} {
+		if object(_s) != _expected {
+			t.Fatalf("%q: unexpected object(); expected %v", _s, _expected)
+		}
+	}
+} // TestObject()
+
+func TestLoad(t *testing.T) {
+	// if we don't have git installed, then skip this test
+	if !gittools.HasGit() {
+		t.Skip("git not installed")
+	}
+
+	_dir, _err := ioutil.TempDir("", "")
+	if _err != nil {
+		t.Fatalf("unable to create temporary directory: %s", _err.Error())
+	}
+	defer os.RemoveAll(_dir)
+	_dir, _ = filepath.EvalSymlinks(_dir)
+	run(t, _dir, "init", "-q")
+	run(t, _dir, "symbolic-ref", "HEAD", "refs/heads/main")
+	_commit := commit(t, _dir, "first")
+	run(t, _dir, "checkout", "-q", "--detach")
+
+	// simulate a GitHub Actions build of the working copy
+	for _env, _value := range map[string]string{
+		"GITHUB_ACTIONS":  "true",
+		"GITHUB_SHA":      _commit,
+		"GITHUB_REF":      "refs/heads/feature",
+		"GITHUB_HEAD_REF": "",
+		"GITHUB_RUN_ID":   "42",
+	} {
+		_previous, _ok := os.LookupEnv(_env)
+		if _ok {
+			defer os.Setenv(_env, _previous)
+		} else {
+			defer os.Unsetenv(_env)
+		}
+		os.Setenv(_env, _value)
+	}
+
+	// the CI environment is recognised unless -no-ci is given
+	_flag := flag.Lookup("no-ci")
+	if _flag == nil || _flag.DefValue != "false" {
+		t.Fatal("unexpected -no-ci default")
+	}
+	for _ci, _expected := range map[bool]string{true: "feature", false: ""} {
+		_info, _err := load(_dir, gitinfo.ModifiedOptions{}, _ci)
+		if _err != nil {
+			t.Fatalf("unexpected error from load(): %s", _err.Error())
+		}
+		_map := _info.Map()
+		if _map[gitinfo.CI_BRANCH] != _expected {
+			t.Fatalf(
+				"ci %v: unexpected %s; expected %q, got %q",
+				_ci, gitinfo.CI_BRANCH, _expected, _map[gitinfo.CI_BRANCH],
+			)
+		}
+	}
+} // TestLoad()
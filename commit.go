@@ -1,5 +1,16 @@
 package gitinfo
 
+import (
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/denormal/go-gitconfig"
+	"github.com/denormal/go-gittools"
+)
+
 // Commit represents a git commit hash.
 type Commit interface {
 	// String returns the full commit hash.
@@ -7,14 +18,65 @@ type Commit interface {
 
 	// Prefix returns the first n characters of the commit hash.
 	Prefix(n int) string
+
+	// Message returns the commit message, or the empty string if the commit
+	// details are not available (e.g. for commits created by Build()). An
+	// error is returned if there is a problem extracting the message.
+	Message() (string, error)
+
+	// Trailers returns the trailers of the commit message, keyed by the
+	// trailer token as given in the message, with the values given in
+	// message order. Trailers are identified using the rules of
+	// "git interpret-trailers", honouring the "trailer.separators" and
+	// "trailer.<token>.*" configuration, such that tokens configured with
+	// a "trailer.<token>.key" are keyed by that key (e.g. "Signed-off-by"
+	// for "signed-off-by"). If the commit message cannot be determined,
+	// Trailers returns an empty map.
+	Trailers() map[string][]string
+
+	// CoAuthors returns the users named by the "Co-authored-by" trailers of
+	// the commit message.
+	CoAuthors() []User
+
+	// Signature returns the signature details of the commit. If the commit
+	// is not signed, or the commit details are not available, the signature
+	// status is SIGNATURE_NONE. An error is returned if there is a problem
+	// extracting the signature.
+	Signature() (Signature, error)
+
+	// Time returns the committer time of the commit, or the zero time if
+	// the commit details are not available (e.g. for commits created by
+	// Build()). An error is returned if there is a problem extracting the
+	// commit time.
+	Time() (time.Time, error)
+
+	// LogValue returns the commit hash as a slog value, so that a Commit
+	// may be logged directly with log/slog.
+	LogValue() slog.Value
 }
 
 type commit struct {
 	commit string
+	config gitconfig.GitConfig
+
+	// the commit message is loaded on demand
+	once    sync.Once
+	message string
+	err     error
+
+	// the commit signature is loaded on demand
+	signed       sync.Once
+	signature    Signature
+	signatureErr error
+
+	// the commit time is loaded on demand
+	timed   sync.Once
+	time    time.Time
+	timeErr error
 }
 
-func newCommit(hash string) Commit {
-	return &commit{commit: hash}
+func newCommit(config gitconfig.GitConfig, hash string) *commit {
+	return &commit{commit: hash, config: config}
 } // newCommit()
 
 func (c *commit) String() string { return c.commit }
@@ -30,5 +92,139 @@ func (c *commit) Prefix(n int) string {
 	}
 } // Prefix()
 
+// Message returns the commit message, or the empty string if the commit
+// details are not available (e.g. for commits created by Build()). An
+// error is returned if there is a problem extracting the message.
+func (c *commit) Message() (string, error) {
+	c.once.Do(func() {
+		// do we have a working copy to examine?
+		if c.config == nil || c.config.Root() == "" || c.commit == "" {
+			return
+		}
+
+		var _bytes []byte
+		_bytes, c.err = gittools.RunInPath(
+			c.config.Root(), "show", "-s", "--format=%B", c.commit,
+		)
+		c.message = strings.TrimRight(string(_bytes), "\n")
+	})
+
+	return c.message, c.err
+} // Message()
+
+// Time returns the committer time of the commit, or the zero time if the
+// commit details are not available. An error is returned if there is a
+// problem extracting the commit time.
+func (c *commit) Time() (time.Time, error) {
+	c.timed.Do(func() {
+		// do we have a working copy to examine?
+		if c.config == nil || c.config.Root() == "" || c.commit == "" {
+			return
+		}
+
+		_bytes, _err := gittools.RunInPath(
+			c.config.Root(), "show", "-s", "--format=%ct", c.commit,
+		)
+		if _err != nil {
+			c.timeErr = _err
+			return
+		}
+		_seconds, _err := strconv.ParseInt(
+			strings.TrimSpace(string(_bytes)), 10, 64,
+		)
+		if _err != nil {
+			c.timeErr = _err
+			return
+		}
+		c.time = time.Unix(_seconds, 0).UTC()
+	})
+
+	return c.time, c.timeErr
+} // Time()
+
+// Trailers returns the trailers of the commit message, keyed by the
+// trailer token as given in the message, or its configured key, with the
+// values given in message order.
+func (c *commit) Trailers() map[string][]string {
+	_trailers := make(map[string][]string)
+	_message, _ := c.Message()
+	for _, _trailer := range trailers(_message, c.separators(), c.tokens()) {
+		_trailers[_trailer.token] = append(
+			_trailers[_trailer.token], _trailer.value,
+		)
+	}
+
+	return _trailers
+} // Trailers()
+
+// CoAuthors returns the users named by the "Co-authored-by" trailers of
+// the commit message.
+func (c *commit) CoAuthors() []User {
+	_users := make([]User, 0)
+	_message, _ := c.Message()
+	for _, _trailer := range trailers(_message, c.separators(), c.tokens()) {
+		if strings.EqualFold(_trailer.token, CO_AUTHORED_BY) {
+			_users = append(_users, parseUser(_trailer.value))
+		}
+	}
+
+	return _users
+} // CoAuthors()
+
+// separators returns the trailer separators configured for the working
+// copy, defaulting to ":"
+func (c *commit) separators() string {
+	if c.config != nil {
+		_config := c.config.Get("trailer.separators")
+		if _config != nil && _config.String() != "" {
+			return _config.String()
+		}
+	}
+
+	return _SEPARATORS
+} // separators()
+
+// tokens returns the trailer tokens configured for the working copy by
+// "trailer.<token>.*" settings, in configuration order
+func (c *commit) tokens() []configured {
+	if c.config == nil || c.config.Root() == "" {
+		return nil
+	}
+
+	// list the trailer settings
+	//		- git exits with an error if there are no matching entries
+	_output, _err := gittools.RunInPath(
+		c.config.Root(), "config", "--get-regexp", `^trailer\..+\.`,
+	)
+	if _err != nil {
+		return nil
+	}
+	_tokens := make([]configured, 0)
+	_index := make(map[string]int)
+	for _, _line := range lines(_output) {
+		_parts := strings.SplitN(_line, " ", 2)
+		_name := strings.TrimPrefix(_parts[0], "trailer.")
+		_i := strings.LastIndex(_name, ".")
+		if _i < 0 || !_SETTINGS[_name[_i+1:]] {
+			continue
+		}
+		_setting := _name[_i+1:]
+		_name = _name[:_i]
+
+		// record the token, and its key
+		_n, _ok := _index[_name]
+		if !_ok {
+			_n = len(_tokens)
+			_index[_name] = _n
+			_tokens = append(_tokens, configured{name: _name})
+		}
+		if _setting == "key" && len(_parts) == 2 {
+			_tokens[_n].key = strings.TrimSpace(_parts[1])
+		}
+	}
+
+	return _tokens
+} // tokens()
+
 // ensure commit implements the Commit interface
 var _ Commit = &commit{}
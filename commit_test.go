@@ -4,6 +4,7 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/denormal/go-gitinfo"
 	"github.com/denormal/go-gittools"
@@ -97,3 +98,40 @@ func TestCommit(t *testing.T) {
 		}
 	}
 } // TestCommit()
+
+func TestCommitTime(t *testing.T) {
+	// if we don't have git installed, then skip this test
+	if !gittools.HasGit() {
+		t.Skip("git not installed")
+	}
+
+	// does Time() report the committer time?
+	_dir := repository(t)
+	defer os.RemoveAll(_dir)
+	commit(t, _dir, "2024-02-29T12:34:56Z", "initial")
+
+	_info, _err := gitinfo.NewWithPath(_dir)
+	if _err != nil {
+		t.Fatalf("unexpected error from NewWithPath(): %s", _err.Error())
+	}
+	_commit, _err := _info.Commit()
+	if _err != nil {
+		t.Fatalf("unexpected error from Commit(): %s", _err.Error())
+	}
+	_expected := time.Date(2024, 2, 29, 12, 34, 56, 0, time.UTC)
+	_time, _err := _commit.Time()
+	if _err != nil {
+		t.Fatalf("unexpected error from Time(): %s", _err.Error())
+	} else if !_time.Equal(_expected) {
+		t.Fatalf("unexpected Time(); expected %s, got %s", _expected, _time)
+	}
+
+	// commits from Build() have no time
+	_commit, _ = gitinfo.Build(_info.Map()).Commit()
+	_time, _err = _commit.Time()
+	if _err != nil {
+		t.Fatalf("unexpected error from Time(): %s", _err.Error())
+	} else if !_time.IsZero() {
+		t.Fatalf("unexpected Time(); expected zero time, got %s", _time)
+	}
+} // TestCommitTime()
@@ -0,0 +1,163 @@
+package gitinfo
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/denormal/go-gittools"
+)
+
+// CountOptions controls the commits counted by CommitCount.
+type CountOptions struct {
+	// FirstParent restricts the count to the first-parent history of HEAD,
+	// so that commits merged from other branches are not counted.
+	FirstParent bool
+
+	// SinceTag restricts the count to the commits since the most recent tag
+	// reachable from HEAD. If HEAD is not preceded by a tag, all commits
+	// are counted.
+	SinceTag bool
+
+	// Match restricts the tags considered by SinceTag to those matching the
+	// given glob pattern (e.g. "v*"). If Match is "", all tags are
+	// considered.
+	Match string
+}
+
+// String returns the options as recorded alongside a commit count in the
+// build information, as a comma-separated list of first-parent, since-tag
+// and match=<pattern>, in that order. The default options are recorded as "".
+func (o CountOptions) String() string {
+	_options := []string{}
+	if o.FirstParent {
+		_options = append(_options, "first-parent")
+	}
+	if o.SinceTag {
+		_options = append(_options, "since-tag")
+	}
+	if o.Match != "" {
+		_options = append(_options, "match="+o.Match)
+	}
+
+	return strings.Join(_options, ",")
+} // String()
+
+// ParseCountOptions returns the CountOptions recorded by
+// CountOptions.String(), such as in the count.options field of generated
+// build information. Unrecognised options are ignored.
+func ParseCountOptions(s string) CountOptions {
+	var _options CountOptions
+	for s != "" {
+		// the pattern may contain commas, so it must be the last option
+		if strings.HasPrefix(s, "match=") {
+			_options.Match = strings.TrimPrefix(s, "match=")
+			break
+		}
+
+		_parts := strings.SplitN(s, ",", 2)
+		switch _parts[0] {
+		case "first-parent":
+			_options.FirstParent = true
+		case "since-tag":
+			_options.SinceTag = true
+		}
+		if len(_parts) == 1 {
+			break
+		}
+		s = _parts[1]
+	}
+
+	return _options
+} // ParseCountOptions()
+
+// counts caches the commit counts that cannot change, i.e. those not
+// restricted to the commits since a tag, by commit and options
+type counts struct {
+	sync.Mutex
+	cache map[CountOptions]map[string]int
+}
+
+// CommitCount returns the number of commits reachable from the HEAD commit
+// of the working copy, as restricted by the given options. An error is
+// returned if the GitInfo instance was initialised for a path not within a
+// working copy, or the HEAD commit cannot be determined. If the working
+// copy is a shallow clone missing some of the counted commits, CommitCount
+// returns a ShallowCloneError.
+func (g *gitinfo) CommitCount(opts CountOptions) (int, error) {
+	_root := g.Root()
+	if _root == "" {
+		return 0, MissingWorkingCopyError
+	}
+
+	// do we have a commit?
+	_commit, _err := g.Commit()
+	if _err != nil {
+		return 0, _err
+	} else if _commit == nil {
+		return 0, nil
+	}
+
+	// have we already counted the commits?
+	//		- counts since the most recent tag change as tags are added
+	if !opts.SinceTag && g.counts != nil {
+		g.counts.Lock()
+		defer g.counts.Unlock()
+		_count, _ok := g.counts.cache[opts][_commit.String()]
+		if _ok {
+			return _count, nil
+		}
+	}
+
+	// determine the range of commits to count
+	_range := _commit.String()
+	if opts.SinceTag {
+		_args := []string{"describe", "--tags", "--abbrev=0"}
+		if opts.Match != "" {
+			_args = append(_args, "--match", opts.Match)
+		}
+		_args = append(_args, _range)
+
+		// if no tag precedes HEAD, we count all commits
+		_tag, _err := gittools.RunInPath(_root, _args...)
+		if _err == nil {
+			_range = "refs/tags/" + strings.TrimSpace(string(_tag)) + ".." +
+				_range
+		}
+	}
+
+	// ensure the history is complete
+	_args := []string{_range}
+	if opts.FirstParent {
+		_args = append(_args, "--first-parent")
+	}
+	_err = g.complete(_args...)
+	if _err != nil {
+		return 0, _err
+	}
+
+	// count the commits
+	_args = append([]string{"rev-list", "--count"}, _args...)
+	_output, _err := gittools.RunInPath(_root, _args...)
+	if _err != nil {
+		return 0, _err
+	}
+
+	_count, _err := strconv.Atoi(strings.TrimSpace(string(_output)))
+	if _err != nil {
+		return 0, _err
+	}
+
+	// cache the count, if it cannot change
+	if !opts.SinceTag && g.counts != nil {
+		if g.counts.cache == nil {
+			g.counts.cache = make(map[CountOptions]map[string]int)
+		}
+		if g.counts.cache[opts] == nil {
+			g.counts.cache[opts] = make(map[string]int)
+		}
+		g.counts.cache[opts][_commit.String()] = _count
+	}
+
+	return _count, nil
+} // CommitCount()
@@ -0,0 +1,145 @@
+package gitinfo_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/denormal/go-gitinfo"
+	"github.com/denormal/go-gittools"
+)
+
+func TestCommitCount(t *testing.T) {
+	// if we don't have git installed, then skip this test
+	if !gittools.HasGit() {
+		t.Skip("git not installed")
+	}
+
+	_dir := repository(t)
+	defer os.RemoveAll(_dir)
+
+	// create the history
+	//		- two commits on the main line, the second tagged
+	//		- two commits on a merged branch
+	//		- one further commit on the main line
+	commit(t, _dir, "2024-01-01T00:00:00Z", "first")
+	commit(t, _dir, "2024-01-02T00:00:00Z", "second", "v1.0.0")
+	git(t, _dir, "checkout", "-q", "-b", "side")
+	commit(t, _dir, "2024-01-03T00:00:00Z", "side one")
+	commit(t, _dir, "2024-01-04T00:00:00Z", "side two", "side-tag")
+	git(t, _dir, "checkout", "-q", "-")
+	git(t, _dir,
+		"-c", "user.name=gitinfo", "-c", "user.email=gitinfo@example.com",
+		"merge", "-q", "--no-ff", "-m", "merge", "side",
+	)
+	commit(t, _dir, "2024-01-05T00:00:00Z", "third")
+
+	count(t, _dir, gitinfo.CountOptions{}, 6)
+	count(t, _dir, gitinfo.CountOptions{FirstParent: true}, 4)
+	count(t, _dir, gitinfo.CountOptions{SinceTag: true}, 2)
+	count(t, _dir, gitinfo.CountOptions{SinceTag: true, Match: "v*"}, 4)
+	count(t, _dir,
+		gitinfo.CountOptions{FirstParent: true, SinceTag: true, Match: "v*"},
+		2,
+	)
+	count(t, _dir, gitinfo.CountOptions{SinceTag: true, Match: "x*"}, 6)
+
+	// the count should be reported by the map
+	_info, _err := gitinfo.NewWithPath(_dir)
+	if _err != nil {
+		t.Fatalf("unexpected error from NewWithPath(): %s", _err.Error())
+	} else if _count := _info.Map()[gitinfo.COUNT]; _count != "6" {
+		t.Fatalf(
+			"unexpected %s; expected %q, got %q", gitinfo.COUNT, "6", _count,
+		)
+	}
+
+	// the count should follow new commits
+	commit(t, _dir, "2024-01-06T00:00:00Z", "fourth")
+	if _count := _info.Map()[gitinfo.COUNT]; _count != "7" {
+		t.Fatalf(
+			"unexpected %s; expected %q, got %q", gitinfo.COUNT, "7", _count,
+		)
+	}
+
+	// the build information should only report the count for the options
+	// used to count the commits
+	for _, _opts := range []gitinfo.CountOptions{
+		{},
+		{FirstParent: true},
+		{SinceTag: true, Match: "v*,x*"},
+		{FirstParent: true, SinceTag: true, Match: "match=*"},
+	} {
+		_build := gitinfo.Build(map[string]string{
+			gitinfo.COUNT:         "4",
+			gitinfo.COUNT_OPTIONS: _opts.String(),
+		})
+		_count, _err := _build.CommitCount(_opts)
+		if _err != nil {
+			t.Fatalf("%+v: unexpected error from Build().CommitCount(): %s",
+				_opts, _err.Error(),
+			)
+		} else if _count != 4 {
+			t.Fatalf(
+				"%+v: unexpected Build().CommitCount(); expected %d, got %d",
+				_opts, 4, _count,
+			)
+		} else if _build.Map()[gitinfo.COUNT_OPTIONS] != _opts.String() {
+			t.Fatalf(
+				"%+v: unexpected %s; expected %q, got %q",
+				_opts, gitinfo.COUNT_OPTIONS,
+				_opts.String(), _build.Map()[gitinfo.COUNT_OPTIONS],
+			)
+		}
+		_other := gitinfo.CountOptions{FirstParent: !_opts.FirstParent}
+		_, _err = _build.CommitCount(_other)
+		if _err != gitinfo.MissingWorkingCopyError {
+			t.Fatalf(
+				"%+v: unexpected error from Build().CommitCount(%+v); "+
+					"expected %v, got %v",
+				_opts, _other, gitinfo.MissingWorkingCopyError, _err,
+			)
+		}
+	}
+
+	// shallow clones cannot be counted
+	_clone := filepath.Join(_dir, "clone")
+	git(t, _dir, "clone", "-q", "--depth", "1", "file://"+_dir, _clone)
+	_info, _err = gitinfo.NewWithPath(_clone)
+	if _err != nil {
+		t.Fatalf("unexpected error from NewWithPath(): %s", _err.Error())
+	}
+	_, _err = _info.CommitCount(gitinfo.CountOptions{})
+	if _, _ok := _err.(*gitinfo.ShallowCloneError); !_ok {
+		t.Fatalf(
+			"unexpected error from CommitCount(); expected %T, got %v",
+			&gitinfo.ShallowCloneError{}, _err,
+		)
+	} else if _count := _info.Map()[gitinfo.COUNT]; _count != "" {
+		t.Fatalf(
+			"unexpected %s; expected %q, got %q", gitinfo.COUNT, "", _count,
+		)
+	}
+} // TestCommitCount()
+
+//
+// helper functions
+//
+
+func count(t *testing.T, dir string, opts gitinfo.CountOptions, expected int) {
+	_info, _err := gitinfo.NewWithPath(dir)
+	if _err != nil {
+		t.Fatalf("unexpected error from NewWithPath(): %s", _err.Error())
+	}
+	_count, _err := _info.CommitCount(opts)
+	if _err != nil {
+		t.Fatalf("%+v: unexpected error from CommitCount(): %s",
+			opts, _err.Error(),
+		)
+	} else if _count != expected {
+		t.Fatalf(
+			"%+v: unexpected count; expected %d, got %d",
+			opts, expected, _count,
+		)
+	}
+} // count()
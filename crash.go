@@ -0,0 +1,179 @@
+package gitinfo
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"runtime/debug"
+	"strconv"
+	"strings"
+
+	"github.com/denormal/go-gitinfo/links"
+)
+
+// _FRAME matches the source location of a stack trace frame, of the form
+// "\t/path/to/file.go:42 +0x1d"
+var _FRAME = regexp.MustCompile(`^\t(.+):([0-9]+)( \+0x[0-9a-f]+)?$`)
+
+// _WEB matches the commit path of a commit web URL, as generated by the
+// links package
+var _WEB = regexp.MustCompile(`/(-/)?commits?/[^/]+$`)
+
+// ReportOptions controls the crash reports written by
+// RecoverAndReportWithOptions.
+type ReportOptions struct {
+	// GitInfo is the git information of the crashed program. If GitInfo is
+	// nil, the git information embedded in the running executable is used,
+	// as returned by Inspect.
+	GitInfo GitInfo
+
+	// Permalinks rewrites the source locations of the stack trace within the
+	// working copy as web URLs of the source lines as of the recorded
+	// commit, if the hosting provider of the repository can be determined.
+	Permalinks bool
+}
+
+// RecoverAndReport recovers from a panic, writes a crash report of the panic
+// value, the goroutine stack and the git information embedded in the running
+// executable to w, and then panics again with the recovered value. It must
+// be deferred directly, as in:
+//
+//	defer gitinfo.RecoverAndReport(os.Stderr)
+//
+// See RecoverAndReportWithOptions for details of the crash report.
+func RecoverAndReport(w io.Writer) {
+	if _r := recover(); _r != nil {
+		report(w, _r, debug.Stack(), ReportOptions{})
+		panic(_r)
+	}
+} // RecoverAndReport()
+
+// RecoverAndReportWithOptions recovers from a panic, writes a crash report
+// to w, and then panics again with the recovered value. The crash report
+// gives the panic value, the commit, modified state and description of the
+// git information, and the stack of the panicking goroutine, with source
+// paths within the root of the working copy rewritten relative to the root,
+// or as permalinks if requested. Source paths are left untouched if the root
+// of the working copy is not known, such as for git information generated
+// with "gitinfo -no-paths", or for executables built with -trimpath. As with
+// RecoverAndReport, it must be deferred directly.
+func RecoverAndReportWithOptions(w io.Writer, opts ReportOptions) {
+	if _r := recover(); _r != nil {
+		report(w, _r, debug.Stack(), opts)
+		panic(_r)
+	}
+} // RecoverAndReportWithOptions()
+
+// report writes the crash report of the given panic value and stack to w
+func report(w io.Writer, r interface{}, stack []byte, opts ReportOptions) {
+	// do we have git information?
+	//		- use the git information embedded in this executable
+	_info := opts.GitInfo
+	if _info == nil {
+		if _path, _err := os.Executable(); _err == nil {
+			if _map, _err := Inspect(_path); _err == nil {
+				_info = Build(_map)
+			}
+		}
+	}
+
+	fmt.Fprintf(w, "panic: %v\n\n", r)
+	if _info != nil {
+		_map := _info.Map()
+		for _, _field := range [][2]string{
+			{COMMIT, _map[COMMIT]},
+			{MODIFIED, _map[MODIFIED]},
+			{DESCRIBE, describe(_info)},
+		} {
+			if _field[1] != "" {
+				fmt.Fprintf(w, "%-8s = %s\n", _field[0], _field[1])
+			}
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprint(w, frames(stack, _info, opts.Permalinks))
+} // report()
+
+// frames returns the given goroutine stack, omitting the frames of the panic
+// handling, with source paths within the working copy of the given GitInfo
+// rewritten relative to the working copy root, or as permalinks
+func frames(stack []byte, gi GitInfo, permalinks bool) string {
+	_lines := strings.Split(string(stack), "\n")
+
+	// skip the frames preceding the call to panic
+	//		- each frame is a function line followed by a location line
+	for _i := 1; _i+1 < len(_lines); _i += 2 {
+		if strings.HasPrefix(_lines[_i], "panic(") {
+			_lines = append(_lines[:1], _lines[_i+2:]...)
+			break
+		}
+	}
+
+	// do we know the working copy root?
+	if gi == nil || gi.Root() == "" {
+		return strings.Join(_lines, "\n")
+	}
+	_root := gi.Root()
+
+	// should we attempt to generate permalinks?
+	var (
+		_repository links.Repository
+		_commit     string
+	)
+	if permalinks {
+		_repository, _commit = repository(gi)
+	}
+
+	// rewrite the source locations of the frames
+	for _i, _line := range _lines {
+		_match := _FRAME.FindStringSubmatch(_line)
+		if _match == nil {
+			continue
+		}
+		_file, _ok := relative(_root, _match[1])
+		if !_ok {
+			continue
+		}
+		_location := _file + ":" + _match[2]
+		if _repository != nil {
+			_n, _ := strconv.Atoi(_match[2])
+			_location = _repository.File(_commit, _file, _n)
+		}
+		_lines[_i] = "\t" + _location + _match[3]
+	}
+
+	return strings.Join(_lines, "\n")
+} // frames()
+
+// repository returns the hosted repository and commit of the given GitInfo,
+// or nil if either cannot be determined. The repository is determined from
+// the git configuration of the working copy, or, for git information created
+// by Build(), from the web URL of the commit.
+func repository(gi GitInfo) (links.Repository, string) {
+	_commit, _err := gi.Commit()
+	if _err != nil || _commit == nil || _commit.String() == "" {
+		return nil, ""
+	}
+
+	// do we have the git configuration of the working copy?
+	if _config := gi.Config(); _config != nil {
+		_repository, _err := links.NewWithConfig(_config)
+		if _err != nil {
+			return nil, ""
+		}
+		return _repository, _commit.String()
+	}
+
+	// otherwise, strip the commit from its web URL
+	_url := gi.Map()[URL_COMMIT]
+	if !_WEB.MatchString(_url) {
+		return nil, ""
+	}
+	_repository, _err := links.Parse(_WEB.ReplaceAllString(_url, ""))
+	if _err != nil {
+		return nil, ""
+	}
+
+	return _repository, _commit.String()
+} // repository()
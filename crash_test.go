@@ -0,0 +1,85 @@
+package gitinfo_test
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/denormal/go-gitinfo"
+)
+
+func TestRecoverAndReport(t *testing.T) {
+	// the git information of the crash reports
+	_, _file, _, _ok := runtime.Caller(0)
+	if !_ok {
+		t.Fatal("unexpected error; runtime.Caller() location not available")
+	}
+	_root, _err := filepath.EvalSymlinks(filepath.Dir(_file))
+	if _err != nil {
+		t.Fatalf("unable to resolve %s: %s", filepath.Dir(_file), _err.Error())
+	}
+	_commit := "0123456789abcdef0123456789abcdef01234567"
+	_info := gitinfo.Build(map[string]string{
+		gitinfo.COMMIT:     _commit,
+		gitinfo.MODIFIED:   "false",
+		gitinfo.ROOT:       _root,
+		gitinfo.URL_COMMIT: "https://github.com/org/repo/commit/" + _commit,
+	})
+	_header := "panic: boom\n\n" +
+		"commit   = " + _commit + "\n" +
+		"modified = false\n" +
+		"describe = 0123456789ab\n\n" +
+		"goroutine "
+
+	// are the stack paths relative to the working copy root?
+	_report, _line := crash(t, gitinfo.ReportOptions{GitInfo: _info})
+	_location := fmt.Sprintf("\n\tcrash_test.go:%d +0x", _line)
+	if !strings.HasPrefix(_report, _header) {
+		t.Fatalf("unexpected report header; expected %q, got %q",
+			_header, _report,
+		)
+	} else if !strings.Contains(_report, _location) {
+		t.Fatalf("report missing %q: %q", _location, _report)
+	} else if strings.Contains(_report, "runtime/debug.Stack") {
+		t.Fatalf("report includes the panic handling: %q", _report)
+	}
+
+	// are the stack paths rewritten as permalinks?
+	_report, _line = crash(t,
+		gitinfo.ReportOptions{GitInfo: _info, Permalinks: true},
+	)
+	_location = fmt.Sprintf(
+		"\n\thttps://github.com/org/repo/blob/%s/crash_test.go#L%d +0x",
+		_commit, _line,
+	)
+	if !strings.Contains(_report, _location) {
+		t.Fatalf("report missing %q: %q", _location, _report)
+	}
+} // TestRecoverAndReport()
+
+// crash panics, returning the crash report written with the given options,
+// and the line of the panic
+func crash(t *testing.T, opts gitinfo.ReportOptions) (string, int) {
+	var (
+		_buffer = new(bytes.Buffer)
+		_line   int
+	)
+
+	// the report is written before the panic is propagated
+	func() {
+		defer func() {
+			if _r := recover(); _r != "boom" {
+				t.Fatalf("unexpected panic value %v", _r)
+			}
+		}()
+		defer gitinfo.RecoverAndReportWithOptions(_buffer, opts)
+
+		_, _, _line, _ = runtime.Caller(0)
+		panic("boom")
+	}()
+
+	return _buffer.String(), _line + 1
+} // crash()
@@ -0,0 +1,44 @@
+package gitinfo
+
+import (
+	"sort"
+)
+
+// Difference describes a git information field whose value differs between
+// two GitInfo instances.
+type Difference struct {
+	Field string // the field name
+	A     string // the value of the first instance
+	B     string // the value of the second instance
+}
+
+// Diff returns the fields whose values differ between the Map()
+// representations of a and b, ordered by field name. Fields present in only
+// one of the maps are compared with the empty string.
+func Diff(a, b GitInfo) []Difference {
+	_a, _b := a.Map(), b.Map()
+
+	// compare the fields of both maps
+	_diffs := make([]Difference, 0)
+	for _field, _value := range _a {
+		if _b[_field] != _value {
+			_diffs = append(_diffs, Difference{_field, _value, _b[_field]})
+		}
+	}
+	for _field, _value := range _b {
+		if _, _ok := _a[_field]; !_ok && _value != "" {
+			_diffs = append(_diffs, Difference{_field, "", _value})
+		}
+	}
+	sort.Slice(_diffs, func(i, j int) bool {
+		return _diffs[i].Field < _diffs[j].Field
+	})
+
+	return _diffs
+} // Diff()
+
+// Equal returns true if the Map() representations of a and b have the same
+// field values.
+func Equal(a, b GitInfo) bool {
+	return len(Diff(a, b)) == 0
+} // Equal()
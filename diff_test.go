@@ -0,0 +1,53 @@
+package gitinfo_test
+
+import (
+	"testing"
+
+	"github.com/denormal/go-gitinfo"
+)
+
+func TestDiff(t *testing.T) {
+	_a := gitinfo.Build(map[string]string{
+		gitinfo.BRANCH:   "main",
+		gitinfo.COMMIT:   "0123456789abcdef0123456789abcdef01234567",
+		gitinfo.MODIFIED: "false",
+	})
+	_b := gitinfo.Build(map[string]string{
+		gitinfo.BRANCH:   "main",
+		gitinfo.COMMIT:   "76543210fedcba9876543210fedcba9876543210",
+		gitinfo.MODIFIED: "true",
+	})
+
+	// instances are equal to themselves, and to their copies
+	if !gitinfo.Equal(_a, _a) {
+		t.Fatal("unexpected result from Equal(); expected true, got false")
+	} else if !gitinfo.Equal(_a, gitinfo.Build(_a.Map())) {
+		t.Fatal("unexpected result from Equal(); expected true, got false")
+	} else if gitinfo.Equal(_a, _b) {
+		t.Fatal("unexpected result from Equal(); expected false, got true")
+	}
+
+	// the differences are reported in field order
+	_expected := []gitinfo.Difference{
+		{
+			Field: gitinfo.COMMIT,
+			A:     "0123456789abcdef0123456789abcdef01234567",
+			B:     "76543210fedcba9876543210fedcba9876543210",
+		},
+		{Field: gitinfo.MODIFIED, A: "false", B: "true"},
+	}
+	_diffs := gitinfo.Diff(_a, _b)
+	if len(_diffs) != len(_expected) {
+		t.Fatalf(
+			"unexpected differences; expected %v, got %v", _expected, _diffs,
+		)
+	}
+	for _i, _diff := range _diffs {
+		if _diff != _expected[_i] {
+			t.Fatalf(
+				"unexpected difference; expected %v, got %v",
+				_expected[_i], _diff,
+			)
+		}
+	}
+} // TestDiff()
@@ -0,0 +1,156 @@
+package gitinfo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/denormal/go-gittools"
+)
+
+// DirtyHash returns a hash of the modifications to the working copy relative
+// to its HEAD commit, including staged, unstaged and untracked content, or
+// the empty string if the working copy is not modified. Modifications
+// ignored by Modified() are excluded from the hash. The hash is
+// deterministic, so that two builds from the same commit are only given the
+// same hash if their modifications are identical. An error is returned if
+// the GitInfo instance was initialised for a path not within a working copy,
+// or there is a problem examining the modifications.
+func (g *gitinfo) DirtyHash() (string, error) {
+	_modified, _err := g.Modified()
+	if _err != nil {
+		return "", _err
+	} else if !_modified {
+		return "", nil
+	}
+
+	// extract the modifications
+	_patch, _untracked, _err := g.dirty(g.ignore(g.options))
+	if _err != nil {
+		return "", _err
+	}
+
+	// hash the patch of the tracked changes, followed by the paths and
+	// contents of the untracked files
+	_hash := sha256.New()
+	_hash.Write([]byte("patch\x00"))
+	_hash.Write(_patch)
+	for _, _path := range _untracked {
+		_content, _err := untracked(g.Root(), _path)
+		if _err != nil {
+			return "", _err
+		}
+		_sum := sha256.Sum256(_content)
+		_hash.Write([]byte("\x00untracked\x00" + _path + "\x00"))
+		_hash.Write(_sum[:])
+	}
+
+	return hex.EncodeToString(_hash.Sum(nil)), nil
+} // DirtyHash()
+
+// dirty returns the binary patch of the staged and unstaged changes of the
+// working copy relative to HEAD, and the sorted slash-separated paths of the
+// untracked files, relative to the working copy root, excluding the
+// modifications ignored by the given options
+func (g *gitinfo) dirty(opts ModifiedOptions) ([]byte, []string, error) {
+	_root := g.Root()
+	if _root == "" {
+		return nil, nil, MissingWorkingCopyError
+	}
+
+	// generate the patch of the tracked changes
+	//		- we fix the diff options that may be changed by the git
+	//		  configuration or attributes, so that the patch is reproducible
+	//		  and may be applied with "git apply"
+	//		- a working copy without commits has no tracked changes
+	var _patch []byte
+	_commit, _err := g.Commit()
+	if _err == nil && _commit != nil {
+		_args := []string{
+			"-c", "core.quotePath=true",
+			"-c", "diff.suppressBlankEmpty=false",
+			"diff", "--binary", "--full-index", "--no-color", "--no-ext-diff",
+			"--no-textconv", "--no-renames", "--src-prefix=a/", "--dst-prefix=b/",
+			"-U3", "--inter-hunk-context=0", "--diff-algorithm=myers",
+			"--no-indent-heuristic", "--no-relative", "--submodule=short",
+			"-O" + os.DevNull,
+		}
+		if opts.IgnoreSubmodules {
+			_args = append(_args, "--ignore-submodules=all")
+		}
+		_args = append(_args, _commit.String())
+		_patch, _err = gittools.RunInPath(
+			_root, append(_args, opts.pathspecs()...)...,
+		)
+		if _err != nil {
+			return nil, nil, _err
+		}
+	}
+
+	// list the untracked files
+	_untracked := make([]string, 0)
+	if opts.IgnoreUntracked {
+		return _patch, _untracked, nil
+	}
+	_args := []string{
+		"ls-files", "-z", "--others", "--exclude-standard", "--full-name",
+	}
+	_output, _err := gittools.RunInPath(
+		_root, append(_args, opts.pathspecs()...)...,
+	)
+	if _err != nil {
+		return nil, nil, _err
+	}
+	for _, _path := range strings.Split(string(_output), "\x00") {
+		// untracked nested repositories are listed as directories
+		if _path != "" && !strings.HasSuffix(_path, "/") {
+			_untracked = append(_untracked, _path)
+		}
+	}
+	sort.Strings(_untracked)
+
+	return _patch, _untracked, nil
+} // dirty()
+
+// the git modes of untracked files
+const (
+	_MODE_EXECUTABLE = "100755"
+	_MODE_FILE       = "100644"
+	_MODE_SYMLINK    = "120000"
+)
+
+// untracked returns the content of the untracked file at the given path
+// relative to the working copy root, or the link target if the file is a
+// symbolic link
+func untracked(root, path string) ([]byte, error) {
+	_path := filepath.Join(root, filepath.FromSlash(path))
+	_info, _err := os.Lstat(_path)
+	if _err != nil {
+		return nil, _err
+	} else if _info.Mode()&os.ModeSymlink != 0 {
+		_target, _err := os.Readlink(_path)
+		return []byte(filepath.ToSlash(_target)), _err
+	}
+
+	return ioutil.ReadFile(_path)
+} // untracked()
+
+// mode returns the git mode of the untracked file at the given path relative
+// to the working copy root; a symbolic link, an executable file, or a
+// regular file
+func mode(root, path string) (string, error) {
+	_info, _err := os.Lstat(filepath.Join(root, filepath.FromSlash(path)))
+	if _err != nil {
+		return "", _err
+	} else if _info.Mode()&os.ModeSymlink != 0 {
+		return _MODE_SYMLINK, nil
+	} else if _info.Mode()&0100 != 0 {
+		return _MODE_EXECUTABLE, nil
+	}
+
+	return _MODE_FILE, nil
+} // mode()
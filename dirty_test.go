@@ -0,0 +1,142 @@
+package gitinfo_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/denormal/go-gitinfo"
+	"github.com/denormal/go-gittools"
+)
+
+func TestDirtyHash(t *testing.T) {
+	// if we don't have git installed, then skip this test
+	if !gittools.HasGit() {
+		t.Skip("git not installed")
+	}
+
+	_dir := repository(t)
+	defer os.RemoveAll(_dir)
+	write := func(name, content string) {
+		_err := ioutil.WriteFile(
+			filepath.Join(_dir, name), []byte(content), 0644,
+		)
+		if _err != nil {
+			t.Fatalf("unable to write %s: %s", name, _err.Error())
+		}
+	}
+	write(".gitignore", "ignored\n")
+	write("tracked", "one\n")
+	commit(t, _dir, "2024-01-01T00:00:00Z", "initial")
+
+	// an unmodified working copy has no dirty hash
+	_clean := dirty(t, _dir)
+	if _clean != "" {
+		t.Fatalf("unexpected dirty hash for clean working copy: %q", _clean)
+	}
+
+	// ignored files do not modify the working copy
+	write("ignored", "ignored")
+	if _hash := dirty(t, _dir); _hash != "" {
+		t.Fatalf("unexpected dirty hash for ignored file: %q", _hash)
+	}
+
+	// unstaged, staged and untracked changes all change the hash
+	write("tracked", "two\n")
+	_unstaged := dirty(t, _dir)
+	git(t, _dir, "add", "tracked")
+	_staged := dirty(t, _dir)
+	write("untracked", "three\n")
+	_untracked := dirty(t, _dir)
+	write("untracked", "four\n")
+	_changed := dirty(t, _dir)
+
+	if _unstaged == "" {
+		t.Fatal("expected dirty hash for unstaged change")
+	} else if _staged != _unstaged {
+		t.Fatalf(
+			"unexpected dirty hash for staged change; expected %q, got %q",
+			_unstaged, _staged,
+		)
+	} else if _untracked == _staged || _untracked == "" {
+		t.Fatalf("expected new dirty hash for untracked file: %q", _untracked)
+	} else if _changed == _untracked || _changed == "" {
+		t.Fatalf("expected new dirty hash for changed file: %q", _changed)
+	}
+
+	// the hash is deterministic
+	write("untracked", "three\n")
+	if _hash := dirty(t, _dir); _hash != _untracked {
+		t.Fatalf(
+			"unexpected dirty hash; expected %q, got %q", _untracked, _hash,
+		)
+	}
+
+	// the hash is independent of the diff configuration and attributes
+	for _, _config := range [][2]string{
+		{"diff.context", "10"},
+		{"diff.interHunkContext", "5"},
+		{"diff.algorithm", "patience"},
+		{"diff.indentHeuristic", "true"},
+		{"diff.relative", "true"},
+		{"diff.noprefix", "true"},
+		{"diff.orderFile", filepath.Join(_dir, ".git", "order")},
+		{"diff.upper.textconv", "sed s/t/T/"},
+	} {
+		git(t, _dir, "config", _config[0], _config[1])
+	}
+	_err := ioutil.WriteFile(
+		filepath.Join(_dir, ".git", "info", "attributes"),
+		[]byte("tracked diff=upper\n"), 0644,
+	)
+	if _err == nil {
+		_err = ioutil.WriteFile(
+			filepath.Join(_dir, ".git", "order"), []byte("untracked\n"), 0644,
+		)
+	}
+	if _err != nil {
+		t.Fatalf("unable to write attributes: %s", _err.Error())
+	}
+	if _hash := dirty(t, _dir); _hash != _untracked {
+		t.Fatalf(
+			"unexpected dirty hash with diff configuration; "+
+				"expected %q, got %q", _untracked, _hash,
+		)
+	}
+
+	// the hash is reported by the map
+	_info, _err := gitinfo.NewWithPath(_dir)
+	if _err != nil {
+		t.Fatalf("unexpected error from NewWithPath(): %s", _err.Error())
+	} else if _hash := _info.Map()[gitinfo.MODIFIED_HASH]; _hash != _untracked {
+		t.Fatalf(
+			"unexpected %s; expected %q, got %q",
+			gitinfo.MODIFIED_HASH, _untracked, _hash,
+		)
+	}
+
+	// reverting the changes restores the clean working copy
+	git(t, _dir, "reset", "-q", "--hard")
+	os.Remove(filepath.Join(_dir, "untracked"))
+	if _hash := dirty(t, _dir); _hash != "" {
+		t.Fatalf("unexpected dirty hash for reverted working copy: %q", _hash)
+	}
+} // TestDirtyHash()
+
+//
+// helper functions
+//
+
+func dirty(t *testing.T, dir string) string {
+	_info, _err := gitinfo.NewWithPath(dir)
+	if _err != nil {
+		t.Fatalf("unexpected error from NewWithPath(): %s", _err.Error())
+	}
+	_hash, _err := _info.DirtyHash()
+	if _err != nil {
+		t.Fatalf("unexpected error from DirtyHash(): %s", _err.Error())
+	}
+
+	return _hash
+} // dirty()
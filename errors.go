@@ -8,6 +8,7 @@ import (
 
 var (
 	MissingGitError         = gittools.MissingGitError
+	MissingGitInfoError     = errors.New("no git information found")
 	MissingWorkingCopyError = gittools.MissingWorkingCopyError
 	UnknownCallerError      = errors.New("unable to determine caller")
 )
@@ -2,11 +2,14 @@ package gitinfo
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 	"strconv"
 	"strings"
 
 	"github.com/denormal/go-gitconfig"
+	"github.com/denormal/go-gitinfo/ci"
+	"github.com/denormal/go-gitinfo/links"
 	"github.com/denormal/go-gittools"
 )
 
@@ -18,36 +21,130 @@ const _EDITOR = "vi"
 type GitInfo interface {
 	// Branch returns the current branch name for the working copy. If the
 	// GitInfo instance was initialised for a path not within a working copy,
-	// Branch will return the empty string. An error is returned if there is
-	// a problem determining the branch name.
+	// Branch will return the empty string. If the working copy has a
+	// detached HEAD, and the GitInfo instance was created with NewWithCI for
+	// the commit being built by a recognised CI provider, Branch returns the
+	// branch being built by CI. An error is returned if there is a problem
+	// determining the branch name.
 	Branch() (string, error)
 
+	// Changelog returns the Changelog for the commits reachable from to,
+	// that are not reachable from from. If from is "", the changelog starts
+	// from the most recent semantic version tag reachable from to,
+	// excluding tags of the to commit itself. If to is "", the changelog
+	// ends with HEAD. An error is returned if the GitInfo instance was
+	// initialised for a path not within a working copy, or there is a
+	// problem extracting the commit history. If the commits are truncated
+	// by the boundary of a shallow clone, a ShallowCloneError is returned.
+	Changelog(from, to string) (Changelog, error)
+
+	// CI returns the continuous integration environment of the current
+	// process, if the GitInfo instance was created with NewWithCI and the
+	// commit being built is the HEAD commit of the working copy, otherwise
+	// nil. See the ci package for details of the supported providers.
+	CI() ci.CI
+
 	// Commit returns the most recent Commit details for the working
 	// copy. If the GitInfo instance was initialised for a path not within a
 	// working copy, Commit will return nil. An error is returned if there is
 	// a problem determining the commit details.
 	Commit() (Commit, error)
 
+	// CommitCount returns the number of commits reachable from the HEAD
+	// commit of the working copy, as restricted by the given options,
+	// providing a monotonically increasing build number. An error is
+	// returned if the GitInfo instance was initialised for a path not within
+	// a working copy, or the HEAD commit cannot be determined. If the
+	// working copy is a shallow clone missing some of the counted commits,
+	// a ShallowCloneError is returned.
+	CommitCount(opts CountOptions) (int, error)
+
+	// Commits returns the commits reachable from to, that are not reachable
+	// from from, most recent first. If from is "", all commits reachable
+	// from to are returned. If to is "", Commits returns the commits
+	// reachable from HEAD. An error is returned if the GitInfo instance was
+	// initialised for a path not within a working copy, or there is a
+	// problem extracting the commit history. If the commits are truncated
+	// by the boundary of a shallow clone, a ShallowCloneError is returned.
+	Commits(from, to string) ([]Commit, error)
+
 	// Config returns the git configuration details for the working copy.
 	// see https://github.com/denormal/go-gitconfig for more details.
 	Config() gitconfig.GitConfig
 
+	// DirtyHash returns a hash of the modifications to the working copy
+	// relative to its HEAD commit, including staged, unstaged and untracked
+	// content, or the empty string if the working copy is not modified. The
+	// hash is deterministic, distinguishing builds from the same commit with
+	// different modifications. An error is returned if the GitInfo instance
+	// was initialised for a path not within a working copy, or there is a
+	// problem examining the modifications.
+	DirtyHash() (string, error)
+
 	// Editor returns the git editor configured for working copy.
 	Editor() string
 
+	// IsShallow returns true if the working copy is a shallow clone, with
+	// incomplete history. Operations requiring history missing from a
+	// shallow clone return a ShallowCloneError. An error is returned if the
+	// GitInfo instance was initialised for a path not within a working copy,
+	// or there is a problem determining the shallow state.
+	IsShallow() (bool, error)
+
 	// Modified returns true if the working copy has been modified, either
-	// through locally made changes, or untracked files. Modified returns an
-	// error if a problem is encountered determining the modified state.
+	// through locally made changes, or untracked files. Modifications
+	// excluded by the IGNORE file of the working copy, or the options given
+	// to NewWithOptions, are ignored. Modified returns an error if a problem
+	// is encountered determining the modified state.
 	Modified() (bool, error)
 
+	// ModifiedWithOptions returns true if the working copy has been
+	// modified, as with Modified, ignoring the modifications excluded by the
+	// given options and the IGNORE file of the working copy.
+	// ModifiedWithOptions returns an error if a problem is encountered
+	// determining the modified state.
+	ModifiedWithOptions(opts ModifiedOptions) (bool, error)
+
+	// PartialCloneFilter returns the object filter of a partial clone (e.g.
+	// "blob:none"), or the empty string if the working copy is not a partial
+	// clone. An error is returned if the GitInfo instance was initialised for
+	// a path not within a working copy.
+	PartialCloneFilter() (string, error)
+
+	// Patch returns the Patch of the uncommitted modifications of the
+	// working copy, relative to its HEAD commit, including the contents of
+	// untracked files, so that the source of a build may be reproduced.
+	// Only the modifications excluded by the options given to
+	// NewWithOptions are omitted from the patch. An error is returned if the
+	// GitInfo instance was initialised for a path not within a working copy,
+	// the working copy has no commits, or there is a problem extracting the
+	// modifications.
+	Patch() (Patch, error)
+
 	// Path returns the absolute path used to initialised this GitInfo.
 	Path() string
 
+	// PseudoVersion returns the Go pseudo-version for the HEAD commit of the
+	// working copy, as reported by "go list -m". If HEAD is tagged with a
+	// canonical semantic version, that version is returned. If the GitInfo
+	// instance was initialised for a path not within a working copy,
+	// PseudoVersion returns the empty string. An error is returned if there
+	// is a problem determining the version. If HEAD is not tagged, and the
+	// working copy is a shallow clone, a ShallowCloneError is returned.
+	PseudoVersion() (string, error)
+
 	// Root returns the root directory of the working copy. If the GitInfo
 	// instance was initialised for a path not within a working copy, Root
 	// returns the empty string.
 	Root() string
 
+	// Shallow returns the shallow boundary commits of the working copy,
+	// whose parents are missing from the clone. If the working copy is not
+	// a shallow clone, Shallow returns an empty list. An error is returned
+	// if the GitInfo instance was initialised for a path not within a
+	// working copy, or there is a problem determining the shallow boundary.
+	Shallow() ([]Commit, error)
+
 	// User returns details of the git user for this working copy.
 	User() User
 
@@ -64,10 +161,19 @@ type GitInfo interface {
 
 	// Map returns the git information as a map of strings.
 	Map() map[string]string
+
+	// LogValue returns the branch, commit and modified state of the working
+	// copy as a slog group value, so that a GitInfo may be logged directly
+	// with log/slog. The values are determined each time the GitInfo is
+	// logged.
+	LogValue() slog.Value
 }
 
 type gitinfo struct {
-	config gitconfig.GitConfig
+	config  gitconfig.GitConfig
+	options ModifiedOptions
+	ci      bool    // recognise the CI environment
+	counts  *counts // the cached commit counts
 }
 
 // Config returns the git configuration details for the working copy.
@@ -110,7 +216,7 @@ func (g *gitinfo) Commit() (Commit, error) {
 	}
 
 	// return the commit instance
-	return newCommit(_commit), nil
+	return newCommit(g.config, _commit), nil
 } // Commit()
 
 // Branch returns the current branch name for the working copy. If the GitInfo
@@ -135,9 +241,43 @@ func (g *gitinfo) Branch() (string, error) {
 	}
 
 	// extract the branch name
-	return strings.TrimSpace(string(_bytes)), nil
+	//		- CI checkouts are typically detached, so use the branch
+	//		  reported by CI, if we have it
+	_branch := strings.TrimSpace(string(_bytes))
+	if _branch == "HEAD" {
+		_ci := g.CI()
+		if _ci != nil && _ci.Branch() != "" {
+			return _ci.Branch(), nil
+		}
+	}
+
+	return _branch, nil
 } // Branch()
 
+// CI returns the continuous integration environment of the current process,
+// if the GitInfo instance was created with NewWithCI and the commit being
+// built is the HEAD commit of the working copy, otherwise nil.
+func (g *gitinfo) CI() ci.CI {
+	if !g.ci {
+		return nil
+	}
+
+	// is CI building this working copy?
+	//		- CI environments apply to other working copies of the build,
+	//		  such as dependencies, so ensure the commits match
+	_ci := ci.Detect()
+	if _ci == nil || _ci.Commit() == "" {
+		return nil
+	}
+	_commit, _err := g.Commit()
+	if _err != nil || _commit == nil ||
+		!strings.EqualFold(_commit.String(), _ci.Commit()) {
+		return nil
+	}
+
+	return _ci
+} // CI()
+
 // Editor returns the git editor configured for working copy.
 func (g *gitinfo) Editor() string {
 	// examine the environment for the editor
@@ -161,31 +301,12 @@ func (g *gitinfo) Editor() string {
 } // Editor()
 
 // Modified returns true if the working copy has been modified, either
-// through locally made changes, or untracked files. Modified returns an
-// error if a problem is encountered determining the modified state.
+// through locally made changes, or untracked files. Modifications excluded
+// by the IGNORE file of the working copy, or the options given to
+// NewWithOptions, are ignored. Modified returns an error if a problem is
+// encountered determining the modified state.
 func (g *gitinfo) Modified() (bool, error) {
-	// if we don't have a working copy root, then we can't determine
-	// the modified status
-	_root := g.Root()
-	if _root == "" {
-		return false, MissingWorkingCopyError
-	}
-
-	// attempt to determine the modified status
-	_output, _err := gittools.RunInPath(_root, "status", "--porcelain")
-	if _err != nil {
-		return false, _err
-	}
-
-	// do we have any non-empty lines?
-	for _, _line := range strings.Split(string(_output), "\n") {
-		_line = strings.TrimSpace(_line)
-		if _line != "" {
-			return true, nil
-		}
-	}
-
-	return false, nil
+	return g.ModifiedWithOptions(g.options)
 } // Modified()
 
 // User returns details of the git user for this working copy.
@@ -221,20 +342,22 @@ func (g *gitinfo) Map() map[string]string {
 		_branch, _   = g.Branch()
 		_commit, _   = g.Commit()
 		_modified, _ = g.Modified()
+		_hash, _     = g.DirtyHash()
 		_user        = g.User()
 		_git, _      = g.Git()
 	)
 
 	// build the map
 	_map := map[string]string{
-		BRANCH:     _branch,
-		EDITOR:     g.Editor(),
-		GIT:        _git,
-		MODIFIED:   strconv.FormatBool(_modified),
-		PATH:       g.Path(),
-		ROOT:       g.Root(),
-		USER_NAME:  _user.Name(),
-		USER_EMAIL: _user.Email(),
+		BRANCH:        _branch,
+		EDITOR:        g.Editor(),
+		GIT:           _git,
+		MODIFIED:      strconv.FormatBool(_modified),
+		MODIFIED_HASH: _hash,
+		PATH:          g.Path(),
+		ROOT:          g.Root(),
+		USER_NAME:     _user.Name(),
+		USER_EMAIL:    _user.Email(),
 	}
 
 	// add the commit hash (if known)
@@ -242,13 +365,61 @@ func (g *gitinfo) Map() map[string]string {
 	//		  a value
 	//		- this ensures the map always contains all possible fields
 	if _commit != nil {
+		_signature, _ := _commit.Signature()
 		_map[COMMIT] = _commit.String()
+		_map[COMMIT_SIGNED] = strconv.FormatBool(
+			_signature != nil && _signature.Valid(),
+		)
 	} else {
 		_map[COMMIT] = ""
+		_map[COMMIT_SIGNED] = strconv.FormatBool(false)
+	}
+
+	// add the commit count (if known)
+	_map[COUNT] = ""
+	_count, _err := g.CommitCount(CountOptions{})
+	if _err == nil {
+		_map[COUNT] = strconv.Itoa(_count)
+	}
+
+	// add the CI details
+	for _k, _v := range cimap(g.CI()) {
+		_map[_k] = _v
+	}
+
+	// add the web URL of the commit (if the hosting provider is known)
+	_map[URL_COMMIT] = ""
+	if _commit != nil {
+		_repository, _err := links.NewWithConfig(g.config)
+		if _err == nil {
+			_map[URL_COMMIT] = _repository.Commit(_commit.String())
+		}
 	}
 
 	return _map
 } // Map()
 
+// cimap returns the map of the CI fields for the given CI environment,
+// with empty values if c is nil
+func cimap(c ci.CI) map[string]string {
+	if c == nil {
+		return map[string]string{
+			CI_BRANCH:       "",
+			CI_PIPELINE:     "",
+			CI_PROVIDER:     "",
+			CI_PULL_REQUEST: "",
+			CI_URL:          "",
+		}
+	}
+
+	return map[string]string{
+		CI_BRANCH:       c.Branch(),
+		CI_PIPELINE:     c.Pipeline(),
+		CI_PROVIDER:     c.Provider(),
+		CI_PULL_REQUEST: c.PullRequest(),
+		CI_URL:          c.URL(),
+	}
+} // cimap()
+
 // ensure gitinfo supports the GitInfo interface
 var _ GitInfo = &gitinfo{}
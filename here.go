@@ -1,7 +1,12 @@
 package gitinfo
 
 import (
+	"fmt"
+	"path/filepath"
 	"runtime"
+	"strings"
+
+	"github.com/denormal/go-gitinfo/links"
 )
 
 // Here returns the GitInfo instance referencing the caller's location. If the
@@ -14,3 +19,112 @@ func Here() (GitInfo, error) {
 		return nil, UnknownCallerError
 	}
 } // Here()
+
+// HereLink returns the Location of the caller. If the caller cannot be
+// determined, HereLink returns the UnknownCallerError.
+func HereLink() (Location, error) {
+	_, _file, _line, _ok := runtime.Caller(1)
+	if _ok {
+		return newLocation(_file, _line)
+	} else {
+		return nil, UnknownCallerError
+	}
+} // HereLink()
+
+// Location represents a line of a source file within a git working copy.
+type Location interface {
+	// GitInfo returns the GitInfo instance for the working copy containing
+	// the source file.
+	GitInfo() GitInfo
+
+	// File returns the slash-separated path of the source file relative to
+	// the root of the working copy. If the file is not within a working
+	// copy, File returns the path of the file as given.
+	File() string
+
+	// Line returns the line number within the source file.
+	Line() int
+
+	// Permalink returns the web URL of the line as of the HEAD commit of the
+	// working copy, or the empty string if the URL cannot be determined
+	// (e.g. the working copy has no remote, or the hosting provider is not
+	// known). See the links package for details of the supported providers.
+	Permalink() string
+
+	// String returns the location as "file:line".
+	String() string
+}
+
+type location struct {
+	info      GitInfo
+	file      string
+	line      int
+	permalink string
+}
+
+// newLocation returns the Location for the given line of the given file,
+// or an error if the git information for the file cannot be determined
+func newLocation(file string, line int) (*location, error) {
+	_info, _err := NewWithPath(file)
+	if _err != nil {
+		return nil, _err
+	}
+	_location := &location{info: _info, file: file, line: line}
+
+	// is the file within a working copy?
+	_root := _info.Root()
+	if _root == "" {
+		return _location, nil
+	}
+
+	_relative, _ok := relative(_root, file)
+	if !_ok {
+		return _location, nil
+	}
+	_location.file = _relative
+
+	// construct the permalink for the HEAD commit
+	_commit, _err := _info.Commit()
+	if _err != nil || _commit == nil {
+		return _location, nil
+	}
+	_repository, _err := links.NewWithConfig(_info.Config())
+	if _err == nil {
+		_location.permalink = _repository.File(
+			_commit.String(), _location.file, line,
+		)
+	}
+
+	return _location, nil
+} // newLocation()
+
+// relative returns the slash-separated path of the given file relative to
+// the given working copy root, and true, or false if the file is not within
+// the working copy
+func relative(root, file string) (string, bool) {
+	// the working copy root has its symbolic links resolved
+	_path := file
+	if _resolved, _err := filepath.EvalSymlinks(file); _err == nil {
+		_path = _resolved
+	}
+	_relative, _err := filepath.Rel(root, _path)
+	if _err != nil || _relative == ".." ||
+		strings.HasPrefix(_relative, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+
+	return filepath.ToSlash(_relative), true
+} // relative()
+
+func (l *location) GitInfo() GitInfo  { return l.info }
+func (l *location) File() string      { return l.file }
+func (l *location) Line() int         { return l.line }
+func (l *location) Permalink() string { return l.permalink }
+
+// String returns the location as "file:line".
+func (l *location) String() string {
+	return fmt.Sprintf("%s:%d", l.file, l.line)
+} // String()
+
+// ensure location implements the Location interface
+var _ Location = &location{}
@@ -1,10 +1,13 @@
 package gitinfo_test
 
 import (
+	"path/filepath"
 	"runtime"
+	"strconv"
 	"testing"
 
 	"github.com/denormal/go-gitinfo"
+	"github.com/denormal/go-gitinfo/links"
 	"github.com/denormal/go-gittools"
 )
 
@@ -55,3 +58,57 @@ func TestHere(t *testing.T) {
 		}
 	}
 } // TestHere()
+
+func TestHereLink(t *testing.T) {
+	// if we don't have git installed, then skip this test
+	if !gittools.HasGit() {
+		t.Skip("git not installed")
+	}
+
+	// does HereLink() report this file and line?
+	_, _file, _line, _ok := runtime.Caller(0)
+	_here, _err := gitinfo.HereLink()
+	if !_ok {
+		t.Fatal("unexpected error; runtime.Caller() location not available")
+	} else if _err != nil {
+		t.Fatalf("unexpected error from HereLink(): %s", _err.Error())
+	} else if _here.Line() != _line+1 {
+		t.Fatalf(
+			"unexpected line; expected %d, got %d", _line+1, _here.Line(),
+		)
+	}
+
+	// is the file reported relative to the working copy root?
+	_root := _here.GitInfo().Root()
+	if _root == "" {
+		t.Skip("not in a working copy")
+	}
+	_path, _ := filepath.EvalSymlinks(_file)
+	_relative, _ := filepath.Rel(_root, _path)
+	if _here.File() != filepath.ToSlash(_relative) {
+		t.Fatalf(
+			"unexpected file; expected %q, got %q",
+			filepath.ToSlash(_relative), _here.File(),
+		)
+	} else if _here.String() != _here.File()+":"+strconv.Itoa(_line+1) {
+		t.Fatalf("unexpected string %q", _here.String())
+	}
+
+	// the permalink should reference the line at the HEAD commit, if the
+	// working copy has a known remote
+	_expected := ""
+	_repository, _err := links.NewWithPath(_root)
+	if _err == nil {
+		_commit, _err := _here.GitInfo().Commit()
+		if _err != nil {
+			t.Fatalf("unexpected error from Commit(): %s", _err.Error())
+		}
+		_expected = _repository.File(_commit.String(), _here.File(), _line+1)
+	}
+	if _here.Permalink() != _expected {
+		t.Fatalf(
+			"unexpected permalink; expected %q, got %q",
+			_expected, _here.Permalink(),
+		)
+	}
+} // TestHereLink()
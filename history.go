@@ -0,0 +1,118 @@
+package gitinfo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/denormal/go-gittools"
+)
+
+// Commits returns the commits reachable from to, that are not reachable from
+// from, most recent first. If from is "", all commits reachable from to are
+// returned. If to is "", Commits returns the commits reachable from HEAD.
+// An error is returned if the GitInfo instance was initialised for a path
+// not within a working copy, or there is a problem extracting the commit
+// history. If the commits are truncated by the boundary of a shallow clone,
+// a ShallowCloneError is returned.
+func (g *gitinfo) Commits(from, to string) ([]Commit, error) {
+	_commits, _err := g.log(from, to)
+	if _err != nil {
+		return nil, _err
+	}
+
+	_rtn := make([]Commit, 0, len(_commits))
+	for _, _commit := range _commits {
+		_rtn = append(_rtn, _commit)
+	}
+
+	return _rtn, nil
+} // Commits()
+
+// log returns the commits reachable from to, that are not reachable from
+// from, with their commit messages, passing any additional arguments to
+// "git log".
+func (g *gitinfo) log(from, to string, args ...string) ([]*commit, error) {
+	_root := g.Root()
+	if _root == "" {
+		return nil, MissingWorkingCopyError
+	}
+
+	// determine the range of commits
+	//		- resolve the refs, so they cannot be mistaken for git options
+	if to == "" {
+		to = "HEAD"
+	}
+	_range, _err := g.resolve(to)
+	if _err != nil {
+		return nil, _err
+	}
+	if from != "" {
+		_from, _err := g.resolve(from)
+		if _err != nil {
+			return nil, _err
+		}
+		_range = _from + ".." + _range
+	}
+
+	// ensure the history is complete
+	_err = g.complete(_range)
+	if _err != nil {
+		return nil, _err
+	}
+
+	// extract the commits and their messages
+	_args := append([]string{"log", "--format=%H%x1f%B%x1e"}, args...)
+	_args = append(_args, _range, "--")
+	_output, _err := gittools.RunInPath(_root, _args...)
+	if _err != nil {
+		return nil, _err
+	}
+
+	// parse the commits
+	_commits := make([]*commit, 0)
+	for _, _record := range strings.Split(string(_output), "\x1e") {
+		_fields := strings.SplitN(strings.TrimSpace(_record), "\x1f", 2)
+		if len(_fields) != 2 {
+			continue
+		}
+
+		// the message is already known, so we don't need to load it
+		_commit := newCommit(g.config, _fields[0])
+		_commit.once.Do(func() {
+			_commit.message = strings.TrimRight(_fields[1], "\n")
+		})
+		_commits = append(_commits, _commit)
+	}
+
+	return _commits, nil
+} // log()
+
+// InvalidRefError is the error returned when a ref given to an operation
+// cannot be resolved to a commit of the working copy, or resembles a git
+// option.
+type InvalidRefError struct {
+	// Ref is the invalid ref.
+	Ref string
+}
+
+// Error returns the error message, describing the invalid ref.
+func (e *InvalidRefError) Error() string {
+	return fmt.Sprintf("invalid ref %q", e.Ref)
+} // Error()
+
+// resolve returns the commit hash of the given ref, or an InvalidRefError
+// if the ref begins with "-", so that it may be mistaken for an option, or
+// cannot be resolved to a commit
+func (g *gitinfo) resolve(ref string) (string, error) {
+	if strings.HasPrefix(ref, "-") {
+		return "", &InvalidRefError{Ref: ref}
+	}
+	_output, _err := gittools.RunInPath(
+		g.Root(), "rev-parse", "--verify", "--quiet", ref+"^{commit}",
+	)
+	if _err != nil {
+		return "", &InvalidRefError{Ref: ref}
+	}
+
+	return strings.TrimSpace(string(_output)), nil
+} // resolve()
@@ -0,0 +1,18 @@
+/*
+Package httpgitinfo serves git information over HTTP. Handler serves the
+fields of a GitInfo as JSON, or as text for clients that prefer it, while
+Middleware adds the commit and branch to the response headers of another
+handler:
+
+	_info, _ := gitinfo.Here()
+	http.Handle("/version", httpgitinfo.Handler(_info))
+	http.ListenAndServe(":8080", httpgitinfo.Middleware(_info, http.DefaultServeMux))
+
+Clients may restrict the fields served with the "fields" query parameter,
+as a comma-separated list of field names, or wildcard patterns such as
+"ci.*", and may use the ETag of the response, derived from the commit, the
+format and the fields served, to avoid repeated transfers. The fields
+describing the build environment and the uncommitted source, listed by
+PRIVATE, are only served when selected by name.
+*/
+package httpgitinfo
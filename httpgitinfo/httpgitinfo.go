@@ -0,0 +1,273 @@
+package httpgitinfo
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/denormal/go-gitinfo"
+)
+
+// the response headers added by Middleware
+const (
+	HEADER_BRANCH = "X-Git-Branch"
+	HEADER_COMMIT = "X-Git-Commit"
+)
+
+// the query parameter selecting the fields to serve
+const FIELDS = "fields"
+
+// PRIVATE lists the fields describing the build environment, or the
+// uncommitted source, that Handler only serves when they are selected by
+// name with the FIELDS query parameter.
+var PRIVATE = []string{
+	gitinfo.EDITOR,
+	gitinfo.GIT,
+	gitinfo.MODIFIED_PATCH,
+	gitinfo.PATH,
+	gitinfo.ROOT,
+	gitinfo.USER_EMAIL,
+	gitinfo.USER_NAME,
+}
+
+// the content types served by Handler
+const (
+	_JSON = "application/json"
+	_TEXT = "text/plain"
+)
+
+// Handler returns an http.Handler serving the fields of the given GitInfo
+// to GET and HEAD requests, as a JSON object, or as text lines of the form
+// "field = value" if the request's Accept header prefers "text/plain". The
+// fields served may be restricted with the FIELDS query parameter, while
+// the PRIVATE fields are only served if named by the FIELDS query
+// parameter. Responses carry an ETag derived from the commit, the format
+// and the fields served, such that conditional requests receive "304 Not
+// Modified". The fields are determined once, when Handler is called.
+func Handler(gi gitinfo.GitInfo) http.Handler {
+	_map := gi.Map()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w,
+				http.StatusText(http.StatusMethodNotAllowed),
+				http.StatusMethodNotAllowed,
+			)
+			return
+		}
+
+		// which fields should we serve?
+		_fields, _err := fields(_map, r.URL.Query()[FIELDS])
+		if _err != nil {
+			http.Error(w, _err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// generate the response in the preferred format
+		var (
+			_body []byte
+			_type = negotiate(r.Header.Get("Accept"))
+		)
+		if _type == _TEXT {
+			_body = text(_map, _fields)
+			w.Header().Set("Content-Type", _TEXT+"; charset=utf-8")
+		} else {
+			_selected := make(map[string]string, len(_fields))
+			for _, _field := range _fields {
+				_selected[_field] = _map[_field]
+			}
+			_body, _ = json.Marshal(_selected)
+			_body = append(_body, '\n')
+			w.Header().Set("Content-Type", _JSON)
+		}
+
+		// has the client already seen this response?
+		w.Header().Set("Vary", "Accept")
+		if _etag := etag(_map, _type, _fields); _etag != "" {
+			w.Header().Set("ETag", _etag)
+			if match(r.Header.Get("If-None-Match"), _etag) {
+				w.Header().Del("Content-Type")
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(_body)))
+		if r.Method == http.MethodGet {
+			w.Write(_body)
+		}
+	})
+} // Handler()
+
+// Middleware returns an http.Handler that adds the HEADER_COMMIT and
+// HEADER_BRANCH headers, of the given GitInfo, to the responses of the
+// handler h. Headers are omitted if their value is not known.
+func Middleware(gi gitinfo.GitInfo, h http.Handler) http.Handler {
+	var (
+		_commit    = ""
+		_branch, _ = gi.Branch()
+	)
+	if _c, _err := gi.Commit(); _err == nil && _c != nil {
+		_commit = _c.String()
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _commit != "" {
+			w.Header().Set(HEADER_COMMIT, _commit)
+		}
+		if _branch != "" {
+			w.Header().Set(HEADER_BRANCH, _branch)
+		}
+		h.ServeHTTP(w, r)
+	})
+} // Middleware()
+
+// etag returns the weak entity tag of the given git information fields,
+// served with the given content type, derived from the commit and, for
+// modified working copies, the hash of the modifications, or the empty
+// string if the commit is not known
+func etag(m map[string]string, typ string, fields []string) string {
+	_commit := m[gitinfo.COMMIT]
+	if _commit == "" {
+		return ""
+	} else if m[gitinfo.MODIFIED] == "true" {
+		_commit = _commit + "+" + m[gitinfo.MODIFIED_HASH]
+	}
+
+	// distinguish the representations of the git information
+	_hash := fnv.New32a()
+	_hash.Write([]byte(typ + "\n" + strings.Join(fields, ",")))
+
+	return fmt.Sprintf(`W/"%s-%08x"`, _commit, _hash.Sum32())
+} // etag()
+
+// match returns true if the If-None-Match header value matches the given
+// entity tag, using weak comparison
+func match(header, etag string) bool {
+	for _, _tag := range strings.Split(header, ",") {
+		_tag = strings.TrimSpace(_tag)
+		if _tag == "*" ||
+			strings.TrimPrefix(_tag, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+} // match()
+
+// fields returns the ordered fields of the given map selected by the given
+// query parameter values, each a comma-separated list of field names or
+// wildcard patterns, or all fields other than the PRIVATE fields if no
+// fields are selected. The PRIVATE fields are only selected by name. An
+// error is returned if a selected field is not known.
+func fields(m map[string]string, values []string) ([]string, error) {
+	_private := make(map[string]bool, len(PRIVATE))
+	for _, _field := range PRIVATE {
+		_private[_field] = true
+	}
+	_all := make([]string, 0, len(m))
+	for _field, _ := range m {
+		if !_private[_field] {
+			_all = append(_all, _field)
+		}
+	}
+	sort.Strings(_all)
+
+	// have we been given any fields?
+	_selected := make([]string, 0)
+	for _, _value := range values {
+		for _, _field := range strings.Split(_value, ",") {
+			_field = strings.TrimSpace(_field)
+			if _field == "" {
+				continue
+			}
+
+			// do we have a wildcard pattern (e.g. ci.*)?
+			if strings.HasSuffix(_field, ".*") {
+				_prefix := strings.TrimSuffix(_field, "*")
+				_found := false
+				for _, _f := range _all {
+					if strings.HasPrefix(_f, _prefix) {
+						_selected = append(_selected, _f)
+						_found = true
+					}
+				}
+				if !_found {
+					return nil, fmt.Errorf("unknown field %q", _field)
+				}
+			} else if _, _ok := m[_field]; _ok {
+				_selected = append(_selected, _field)
+			} else {
+				return nil, fmt.Errorf("unknown field %q", _field)
+			}
+		}
+	}
+	if len(_selected) == 0 {
+		return _all, nil
+	}
+
+	return _selected, nil
+} // fields()
+
+// text returns the given fields as lines of the form "field = value", with
+// the field names left-justified
+func text(m map[string]string, fields []string) []byte {
+	_len := 0
+	for _, _field := range fields {
+		if len(_field) > _len {
+			_len = len(_field)
+		}
+	}
+
+	_text := ""
+	for _, _field := range fields {
+		_text += fmt.Sprintf("%-*s = %s\n", _len, _field, m[_field])
+	}
+
+	return []byte(_text)
+} // text()
+
+// negotiate returns the content type to serve for the given Accept header;
+// text/plain if it is preferred to application/json, otherwise
+// application/json
+func negotiate(accept string) string {
+	// record the quality of each media range, so that the most specific
+	// range matching each type determines its quality
+	_quality := make(map[string]float64)
+	for _, _range := range strings.Split(accept, ",") {
+		_type, _params, _err := mime.ParseMediaType(_range)
+		if _err != nil {
+			continue
+		}
+		_q := 1.0
+		if _value, _ok := _params["q"]; _ok {
+			_q, _err = strconv.ParseFloat(_value, 64)
+			if _err != nil {
+				continue
+			}
+		}
+		_quality[_type] = _q
+	}
+	_q := func(t string) float64 {
+		for _, _range := range []string{
+			t, strings.SplitN(t, "/", 2)[0] + "/*", "*/*",
+		} {
+			if _value, _ok := _quality[_range]; _ok {
+				return _value
+			}
+		}
+		return -1
+	}
+
+	// JSON is preferred, unless text is explicitly preferred
+	_json, _text := _q(_JSON), _q(_TEXT)
+	if _text > 0 && _text > _json {
+		return _TEXT
+	}
+	return _JSON
+} // negotiate()
@@ -0,0 +1,227 @@
+package httpgitinfo_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/denormal/go-gitinfo"
+	"github.com/denormal/go-gitinfo/httpgitinfo"
+)
+
+// the git information served by the tests
+var _MAP = map[string]string{
+	gitinfo.BRANCH:     "main",
+	gitinfo.COMMIT:     "0123456789abcdef0123456789abcdef01234567",
+	gitinfo.MODIFIED:   "false",
+	gitinfo.USER_EMAIL: "user@example.com",
+	gitinfo.USER_NAME:  "user",
+}
+
+func TestHandler(t *testing.T) {
+	_handler := httpgitinfo.Handler(gitinfo.Build(_MAP))
+
+	// by default, all fields other than the private fields are served as
+	// JSON
+	_response := serve(t, _handler, "GET", "/version", nil)
+	if _response.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d", _response.Code)
+	} else if _response.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf(
+			"unexpected content type %q",
+			_response.Header().Get("Content-Type"),
+		)
+	}
+	_got := make(map[string]string)
+	_err := json.Unmarshal(_response.Body.Bytes(), &_got)
+	if _err != nil {
+		t.Fatalf("unable to decode response: %s", _err.Error())
+	}
+	_expected := gitinfo.Build(_MAP).Map()
+	for _, _field := range httpgitinfo.PRIVATE {
+		delete(_expected, _field)
+	}
+	if len(_got) != len(_expected) {
+		t.Fatalf("unexpected fields; expected %v, got %v", _expected, _got)
+	}
+	for _k, _v := range _expected {
+		if _got[_k] != _v {
+			t.Fatalf(
+				"unexpected value for %q; expected %q, got %q", _k, _v, _got[_k],
+			)
+		}
+	}
+
+	// private fields are not matched by wildcards
+	_response = serve(t, _handler, "GET", "/version?fields=user.*", nil)
+	if _response.Code != http.StatusBadRequest {
+		t.Fatalf(
+			"unexpected status; expected %d, got %d",
+			http.StatusBadRequest, _response.Code,
+		)
+	}
+
+	// fields may be selected, and served as text
+	//		- private fields are served if selected by name
+	_response = serve(t, _handler, "GET",
+		"/version?fields=commit,user.email,user.name",
+		map[string]string{"Accept": "text/plain, application/json;q=0.5"},
+	)
+	_text := "commit     = 0123456789abcdef0123456789abcdef01234567\n" +
+		"user.email = user@example.com\n" +
+		"user.name  = user\n"
+	if _response.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d", _response.Code)
+	} else if !strings.HasPrefix(
+		_response.Header().Get("Content-Type"), "text/plain",
+	) {
+		t.Fatalf(
+			"unexpected content type %q",
+			_response.Header().Get("Content-Type"),
+		)
+	} else if _response.Body.String() != _text {
+		t.Fatalf(
+			"unexpected body; expected %q, got %q",
+			_text, _response.Body.String(),
+		)
+	}
+
+	// JSON is preferred unless text is explicitly preferred
+	_response = serve(t, _handler, "GET", "/version?fields=branch",
+		map[string]string{"Accept": "text/*;q=0.5, */*"},
+	)
+	if _response.Body.String() != `{"branch":"main"}`+"\n" {
+		t.Fatalf("unexpected body %q", _response.Body.String())
+	}
+
+	// unknown fields are rejected
+	_response = serve(t, _handler, "GET", "/version?fields=nonsense", nil)
+	if _response.Code != http.StatusBadRequest {
+		t.Fatalf(
+			"unexpected status; expected %d, got %d",
+			http.StatusBadRequest, _response.Code,
+		)
+	}
+
+	// responses are tagged with the commit
+	_response = serve(t, _handler, "GET", "/version", nil)
+	_etag := _response.Header().Get("ETag")
+	if !strings.HasPrefix(_etag, `W/"`+_MAP[gitinfo.COMMIT]+`-`) {
+		t.Fatalf("unexpected ETag %q", _etag)
+	}
+	_response = serve(t, _handler, "GET", "/version",
+		map[string]string{"If-None-Match": strings.TrimPrefix(_etag, "W/")},
+	)
+	if _response.Header().Get("ETag") != _etag {
+		t.Fatalf(
+			"unexpected ETag; expected %q, got %q",
+			_etag, _response.Header().Get("ETag"),
+		)
+	} else if _response.Code != http.StatusNotModified {
+		t.Fatalf(
+			"unexpected status; expected %d, got %d",
+			http.StatusNotModified, _response.Code,
+		)
+	} else if _response.Body.Len() != 0 {
+		t.Fatalf("unexpected body %q", _response.Body.String())
+	}
+
+	// the ETag differs for each representation and selection of fields
+	for _, _request := range []struct {
+		target string
+		accept string
+	}{
+		{"/version", "text/plain"},
+		{"/version?fields=commit", ""},
+		{"/version?fields=commit,user.name", ""},
+	} {
+		_response = serve(t, _handler, "GET", _request.target,
+			map[string]string{
+				"Accept":        _request.accept,
+				"If-None-Match": _etag,
+			},
+		)
+		if _response.Header().Get("ETag") == _etag {
+			t.Fatalf(
+				"%s (%s): unexpected ETag %q",
+				_request.target, _request.accept, _etag,
+			)
+		} else if _response.Code != http.StatusOK {
+			t.Fatalf(
+				"%s (%s): unexpected status; expected %d, got %d",
+				_request.target, _request.accept,
+				http.StatusOK, _response.Code,
+			)
+		}
+	}
+
+	// only GET and HEAD are supported
+	_response = serve(t, _handler, "HEAD", "/version", nil)
+	if _response.Code != http.StatusOK || _response.Body.Len() != 0 {
+		t.Fatalf(
+			"unexpected HEAD response %d: %q",
+			_response.Code, _response.Body.String(),
+		)
+	}
+	_response = serve(t, _handler, "POST", "/version", nil)
+	if _response.Code != http.StatusMethodNotAllowed {
+		t.Fatalf(
+			"unexpected status; expected %d, got %d",
+			http.StatusMethodNotAllowed, _response.Code,
+		)
+	}
+} // TestHandler()
+
+func TestMiddleware(t *testing.T) {
+	_handler := httpgitinfo.Middleware(gitinfo.Build(_MAP),
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("ok"))
+		}),
+	)
+
+	// are the git headers added to the response?
+	_response := serve(t, _handler, "GET", "/", nil)
+	if _response.Body.String() != "ok" {
+		t.Fatalf("unexpected body %q", _response.Body.String())
+	}
+	for _header, _expected := range map[string]string{
+		httpgitinfo.HEADER_BRANCH: _MAP[gitinfo.BRANCH],
+		httpgitinfo.HEADER_COMMIT: _MAP[gitinfo.COMMIT],
+	} {
+		if _response.Header().Get(_header) != _expected {
+			t.Fatalf(
+				"unexpected %s header; expected %q, got %q",
+				_header, _expected, _response.Header().Get(_header),
+			)
+		}
+	}
+
+	// unknown values are omitted
+	_handler = httpgitinfo.Middleware(gitinfo.Build(nil), http.NotFoundHandler())
+	_response = serve(t, _handler, "GET", "/", nil)
+	if _, _ok := _response.Header()[httpgitinfo.HEADER_COMMIT]; _ok {
+		t.Fatalf("unexpected %s header", httpgitinfo.HEADER_COMMIT)
+	}
+} // TestMiddleware()
+
+//
+// helper functions
+//
+
+func serve(
+	t *testing.T,
+	h http.Handler,
+	method, target string,
+	headers map[string]string,
+) *httptest.ResponseRecorder {
+	_request := httptest.NewRequest(method, target, nil)
+	for _k, _v := range headers {
+		_request.Header.Set(_k, _v)
+	}
+	_response := httptest.NewRecorder()
+	h.ServeHTTP(_response, _request)
+
+	return _response
+} // serve()
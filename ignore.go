@@ -0,0 +1,124 @@
+package gitinfo
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/denormal/go-gittools"
+)
+
+// IGNORE is the name of the file in the root of a working copy listing the
+// modifications to exclude when determining whether the working copy is
+// modified. Each line of the file is a git pathspec, relative to the root of
+// the working copy, of paths to exclude. Blank lines and lines starting with
+// "#" are ignored, while the lines "@untracked" and "@submodules" exclude
+// all untracked files and all submodule changes, respectively.
+const IGNORE = ".gitinfoignore"
+
+// the IGNORE file directives
+const (
+	_IGNORE_UNTRACKED  = "@untracked"
+	_IGNORE_SUBMODULES = "@submodules"
+)
+
+// ModifiedOptions controls the modifications considered when determining
+// whether a working copy is modified.
+type ModifiedOptions struct {
+	// Exclude lists the git pathspecs, relative to the root of the working
+	// copy, of paths whose modifications are ignored.
+	Exclude []string
+
+	// IgnoreUntracked ignores untracked files.
+	IgnoreUntracked bool
+
+	// IgnoreSubmodules ignores changes to submodules.
+	IgnoreSubmodules bool
+}
+
+// NewWithOptions returns the GitInfo instance for the given path, as with
+// NewWithPath, that applies the given options when determining whether the
+// working copy is modified, such as for Modified(), DirtyHash() and Map().
+func NewWithOptions(path string, opts ModifiedOptions) (GitInfo, error) {
+	_info, _err := NewWithPath(path)
+	if _err != nil {
+		return nil, _err
+	}
+	_info.(*gitinfo).options = opts
+
+	return _info, nil
+} // NewWithOptions()
+
+// ModifiedWithOptions returns true if the working copy has been modified, as
+// with Modified, ignoring the modifications excluded by the given options
+// and the IGNORE file of the working copy. ModifiedWithOptions returns an
+// error if a problem is encountered determining the modified state.
+func (g *gitinfo) ModifiedWithOptions(opts ModifiedOptions) (bool, error) {
+	// if we don't have a working copy root, then we can't determine
+	// the modified status
+	_root := g.Root()
+	if _root == "" {
+		return false, MissingWorkingCopyError
+	}
+
+	// attempt to determine the modified status
+	_opts := g.ignore(opts)
+	_args := []string{"status", "--porcelain"}
+	if _opts.IgnoreUntracked {
+		_args = append(_args, "--untracked-files=no")
+	}
+	if _opts.IgnoreSubmodules {
+		_args = append(_args, "--ignore-submodules=all")
+	}
+	_args = append(_args, _opts.pathspecs()...)
+	_output, _err := gittools.RunInPath(_root, _args...)
+	if _err != nil {
+		return false, _err
+	}
+
+	// do we have any non-empty lines?
+	return len(lines(_output)) != 0, nil
+} // ModifiedWithOptions()
+
+// ignore returns the given options combined with those of the IGNORE file
+// of the working copy
+func (g *gitinfo) ignore(opts ModifiedOptions) ModifiedOptions {
+	_opts := opts
+	_opts.Exclude = append([]string{}, opts.Exclude...)
+
+	// a missing IGNORE file excludes nothing
+	_bytes, _err := ioutil.ReadFile(filepath.Join(g.Root(), IGNORE))
+	if _err != nil {
+		return _opts
+	}
+	for _, _line := range strings.Split(string(_bytes), "\n") {
+		_line = strings.TrimSpace(_line)
+		switch {
+		case _line == "" || strings.HasPrefix(_line, "#"):
+		case _line == _IGNORE_UNTRACKED:
+			_opts.IgnoreUntracked = true
+		case _line == _IGNORE_SUBMODULES:
+			_opts.IgnoreSubmodules = true
+		default:
+			_opts.Exclude = append(_opts.Exclude, _line)
+		}
+	}
+
+	return _opts
+} // ignore()
+
+// pathspecs returns the git pathspec arguments selecting the root of the
+// working copy, without the excluded paths
+func (o ModifiedOptions) pathspecs() []string {
+	_pathspecs := []string{"--", ":(top)"}
+	for _, _exclude := range o.Exclude {
+		// pathspecs may already have magic, which we extend
+		if strings.HasPrefix(_exclude, ":(") {
+			_pathspecs = append(_pathspecs, ":(top,exclude,"+_exclude[2:])
+		} else {
+			_pathspecs = append(_pathspecs, ":(top,exclude)"+_exclude)
+		}
+	}
+
+	return _pathspecs
+} // pathspecs()
@@ -0,0 +1,158 @@
+package gitinfo_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/denormal/go-gitinfo"
+	"github.com/denormal/go-gittools"
+)
+
+func TestModifiedWithOptions(t *testing.T) {
+	// if we don't have git installed, then skip this test
+	if !gittools.HasGit() {
+		t.Skip("git not installed")
+	}
+
+	_dir := repository(t)
+	defer os.RemoveAll(_dir)
+	write := func(name, content string) {
+		_path := filepath.Join(_dir, filepath.FromSlash(name))
+		_err := os.MkdirAll(filepath.Dir(_path), 0755)
+		if _err == nil {
+			_err = ioutil.WriteFile(_path, []byte(content), 0644)
+		}
+		if _err != nil {
+			t.Fatalf("unable to write %s: %s", name, _err.Error())
+		}
+	}
+	write("git.go", "package main\n")
+	write("api/api.pb.go", "package api\n")
+	write("main.go", "package main\n")
+	commit(t, _dir, "2024-01-01T00:00:00Z", "initial")
+
+	// modify the generated files
+	write("git.go", "package main // generated\n")
+	write("api/api.pb.go", "package api // generated\n")
+	modified(t, _dir, gitinfo.ModifiedOptions{}, true)
+	modified(t, _dir,
+		gitinfo.ModifiedOptions{Exclude: []string{"git.go"}},
+		true,
+	)
+	modified(t, _dir,
+		gitinfo.ModifiedOptions{Exclude: []string{"git.go", "*.pb.go"}},
+		false,
+	)
+	modified(t, _dir,
+		gitinfo.ModifiedOptions{
+			Exclude: []string{":(literal)git.go", ":(glob)**/*.pb.go"},
+		},
+		false,
+	)
+
+	// untracked files may be ignored
+	write("untracked", "untracked\n")
+	_opts := gitinfo.ModifiedOptions{Exclude: []string{"git.go", "api"}}
+	modified(t, _dir, _opts, true)
+	_opts.IgnoreUntracked = true
+	modified(t, _dir, _opts, false)
+
+	// NewWithOptions applies the options to Modified()
+	_info, _err := gitinfo.NewWithOptions(_dir, gitinfo.ModifiedOptions{
+		Exclude:         []string{"git.go", "api"},
+		IgnoreUntracked: true,
+	})
+	if _err != nil {
+		t.Fatalf("unexpected error from NewWithOptions(): %s", _err.Error())
+	}
+	_modified, _err := _info.Modified()
+	if _err != nil {
+		t.Fatalf("unexpected error from Modified(): %s", _err.Error())
+	} else if _modified {
+		t.Fatal("unexpected modification from NewWithOptions() instance")
+	}
+
+	// the ignore file applies to all checks
+	write(gitinfo.IGNORE, "# generated files\ngit.go\n\napi/\n@untracked\n")
+	git(t, _dir, "add", gitinfo.IGNORE)
+	git(t, _dir,
+		"-c", "user.name=gitinfo", "-c", "user.email=gitinfo@example.com",
+		"-c", "commit.gpgsign=false",
+		"commit", "-q", "-m", "ignore", "--", gitinfo.IGNORE,
+	)
+	modified(t, _dir, gitinfo.ModifiedOptions{}, false)
+	_info, _err = gitinfo.NewWithPath(_dir)
+	if _err != nil {
+		t.Fatalf("unexpected error from NewWithPath(): %s", _err.Error())
+	}
+	_hash, _err := _info.DirtyHash()
+	if _err != nil {
+		t.Fatalf("unexpected error from DirtyHash(): %s", _err.Error())
+	}
+	_map := _info.Map()
+	if _hash != "" || _map[gitinfo.MODIFIED] != "false" ||
+		_map[gitinfo.MODIFIED_HASH] != "" {
+		t.Fatalf(
+			"unexpected modification; expected none, got %q (%s = %s)",
+			_hash, gitinfo.MODIFIED, _map[gitinfo.MODIFIED],
+		)
+	}
+
+	// modifications that are not ignored are still reported
+	write("main.go", "package main // modified\n")
+	modified(t, _dir, gitinfo.ModifiedOptions{}, true)
+} // TestModifiedWithOptions()
+
+func TestModifiedSubmodules(t *testing.T) {
+	// if we don't have git installed, then skip this test
+	if !gittools.HasGit() {
+		t.Skip("git not installed")
+	}
+
+	_sub := repository(t)
+	defer os.RemoveAll(_sub)
+	commit(t, _sub, "2024-01-01T00:00:00Z", "submodule")
+
+	_dir := repository(t)
+	defer os.RemoveAll(_dir)
+	git(t, _dir,
+		"-c", "protocol.file.allow=always",
+		"submodule", "add", "-q", _sub, "sub",
+	)
+	commit(t, _dir, "2024-01-01T00:00:00Z", "initial")
+	modified(t, _dir, gitinfo.ModifiedOptions{}, false)
+
+	// a new commit within the submodule modifies the working copy
+	commit(t, filepath.Join(_dir, "sub"), "2024-01-02T00:00:00Z", "change")
+	modified(t, _dir, gitinfo.ModifiedOptions{}, true)
+	modified(t, _dir, gitinfo.ModifiedOptions{IgnoreSubmodules: true}, false)
+} // TestModifiedSubmodules()
+
+//
+// helper functions
+//
+
+func modified(
+	t *testing.T,
+	dir string,
+	opts gitinfo.ModifiedOptions,
+	expected bool,
+) {
+	_info, _err := gitinfo.NewWithPath(dir)
+	if _err != nil {
+		t.Fatalf("unexpected error from NewWithPath(): %s", _err.Error())
+	}
+	_modified, _err := _info.ModifiedWithOptions(opts)
+	if _err != nil {
+		t.Fatalf(
+			"unexpected error from ModifiedWithOptions(): %s", _err.Error(),
+		)
+	} else if _modified != expected {
+		t.Fatalf(
+			"%+v: unexpected ModifiedWithOptions(); expected %v, got %v",
+			opts, expected, _modified,
+		)
+	}
+} // modified()
@@ -0,0 +1,348 @@
+package gitinfo
+
+import (
+	"debug/buildinfo"
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"encoding/binary"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Inspect returns the git information fields found within the named
+// compiled Go binary, combining, in increasing order of precedence:
+//
+//   - the version control settings recorded by the go tool (see
+//     debug/buildinfo), providing the commit and modified fields;
+//   - string variables set with "-ldflags -X" whose names match a field
+//     name, optionally prefixed by "git" (e.g. main.commit or
+//     main.gitUserName), if the binary has a symbol table; and
+//   - the field name and value pairs generated by "gitinfo -X".
+//
+// Only the fields that are found are returned. An error is returned if the
+// binary cannot be read, while MissingGitInfoError is returned if no git
+// information is found.
+func Inspect(path string) (map[string]string, error) {
+	_map := make(map[string]string)
+
+	// do we have version control settings from the go tool?
+	_build, _err := buildinfo.ReadFile(path)
+	if _err == nil {
+		_settings := make(map[string]string)
+		for _, _setting := range _build.Settings {
+			_settings[_setting.Key] = _setting.Value
+		}
+		if _settings["vcs"] == "git" {
+			if _commit, _ok := _settings["vcs.revision"]; _ok {
+				_map[COMMIT] = _commit
+			}
+			if _modified, _ok := _settings["vcs.modified"]; _ok {
+				_map[MODIFIED] = _modified
+			}
+		}
+	}
+
+	// load the binary image
+	_image, _err := open(path)
+	if _err != nil {
+		return nil, _err
+	}
+	_fields := fields()
+
+	// look for string variables named after git information fields
+	for _, _symbol := range _image.symbols {
+		_name := normalise(_symbol.name)
+		_field, _ok := _fields[_name]
+		if !_ok {
+			_field, _ok = _fields[strings.TrimPrefix(_name, "git")]
+			if !_ok {
+				continue
+			}
+		}
+		_value, _ok := _image.string(_symbol.addr)
+		if _ok {
+			_map[_field] = _value
+		}
+	}
+
+	// look for the field name and value pairs generated by gitinfo
+	for _k, _v := range _image.pairs(_fields) {
+		_map[_k] = _v
+	}
+
+	if len(_map) == 0 {
+		return nil, MissingGitInfoError
+	}
+	return _map, nil
+} // Inspect()
+
+// fields returns the git information field names, keyed by their
+// normalised form
+func fields() map[string]string {
+	_fields := map[string]string{normalise(MODIFIED_PATCH): MODIFIED_PATCH}
+	for _field, _ := range Build(nil).Map() {
+		_fields[normalise(_field)] = _field
+	}
+
+	return _fields
+} // fields()
+
+// normalise returns the lower-case letters and digits of the given name
+func normalise(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+} // normalise()
+
+// image represents the loaded sections and symbols of a compiled binary
+type image struct {
+	order    binary.ByteOrder
+	ptr      int // the size of a pointer
+	sections []*section
+	symbols  []*symbol
+}
+
+// section is a loaded section of a compiled binary
+type section struct {
+	name string
+	addr uint64
+	data []byte
+}
+
+// symbol is a data symbol of a compiled binary that may name a string
+// variable set with "-ldflags -X", identified by its git information field
+// name
+type symbol struct {
+	name string
+	addr uint64
+}
+
+// open returns the image of the named ELF, Mach-O or PE binary
+func open(path string) (*image, error) {
+	if _file, _err := elf.Open(path); _err == nil {
+		defer _file.Close()
+		return openELF(_file)
+	}
+	if _file, _err := macho.Open(path); _err == nil {
+		defer _file.Close()
+		return openMachO(_file)
+	}
+	_file, _err := pe.Open(path)
+	if _err != nil {
+		return nil, _err
+	}
+	defer _file.Close()
+
+	return openPE(_file)
+} // open()
+
+func openELF(file *elf.File) (*image, error) {
+	_image := &image{order: file.ByteOrder, ptr: 8}
+	if file.Class == elf.ELFCLASS32 {
+		_image.ptr = 4
+	}
+	for _, _section := range file.Sections {
+		if _section.Type == elf.SHT_NOBITS || _section.Addr == 0 {
+			continue
+		}
+		_data, _err := _section.Data()
+		if _err != nil {
+			return nil, _err
+		}
+		_image.sections = append(_image.sections,
+			&section{_section.Name, _section.Addr, _data},
+		)
+	}
+
+	// stripped binaries have no symbols
+	_symbols, _ := file.Symbols()
+	for _, _symbol := range _symbols {
+		if elf.ST_TYPE(_symbol.Info) != elf.STT_OBJECT ||
+			_symbol.Size != uint64(2*_image.ptr) {
+			continue
+		}
+		_image.add(_symbol.Name, _symbol.Value)
+	}
+
+	return _image, nil
+} // openELF()
+
+func openMachO(file *macho.File) (*image, error) {
+	_image := &image{order: file.ByteOrder, ptr: 8}
+	if file.Magic == macho.Magic32 {
+		_image.ptr = 4
+	}
+	for _, _section := range file.Sections {
+		if _section.Offset == 0 {
+			continue
+		}
+		_data, _err := _section.Data()
+		if _err != nil {
+			return nil, _err
+		}
+		_image.sections = append(_image.sections,
+			&section{_section.Name, _section.Addr, _data},
+		)
+	}
+
+	// stripped binaries have no symbols
+	if file.Symtab != nil {
+		for _, _symbol := range file.Symtab.Syms {
+			_image.add(_symbol.Name, _symbol.Value)
+		}
+	}
+
+	return _image, nil
+} // openMachO()
+
+func openPE(file *pe.File) (*image, error) {
+	_image := &image{order: binary.LittleEndian, ptr: 8}
+	var _base uint64
+	switch _header := file.OptionalHeader.(type) {
+	case *pe.OptionalHeader32:
+		_image.ptr, _base = 4, uint64(_header.ImageBase)
+	case *pe.OptionalHeader64:
+		_base = _header.ImageBase
+	}
+	for _, _section := range file.Sections {
+		_data, _err := _section.Data()
+		if _err != nil {
+			return nil, _err
+		}
+		_image.sections = append(_image.sections, &section{
+			_section.Name, _base + uint64(_section.VirtualAddress), _data,
+		})
+	}
+
+	// symbol values are relative to their section
+	for _, _symbol := range file.Symbols {
+		_index := int(_symbol.SectionNumber) - 1
+		if _index < 0 || _index >= len(file.Sections) {
+			continue
+		}
+		_image.add(_symbol.Name,
+			_base+uint64(file.Sections[_index].VirtualAddress)+
+				uint64(_symbol.Value),
+		)
+	}
+
+	return _image, nil
+} // openPE()
+
+// add records the named symbol, if it may be a variable set with
+// "-ldflags -X" of the main package or a package outside the standard
+// library
+func (i *image) add(name string, addr uint64) {
+	_dot := strings.LastIndex(name, ".")
+	if _dot < 0 {
+		return
+	}
+	_package := name[:_dot]
+	if _package != "main" &&
+		!strings.Contains(strings.SplitN(_package, "/", 2)[0], ".") {
+		return
+	}
+	i.symbols = append(i.symbols, &symbol{name[_dot+1:], addr})
+} // add()
+
+// read returns the n bytes of the image at the given address, and true, or
+// false if the bytes are not within a loaded section
+func (i *image) read(addr uint64, n uint64) ([]byte, bool) {
+	for _, _section := range i.sections {
+		_length := uint64(len(_section.data))
+		if addr >= _section.addr && n <= _length &&
+			addr-_section.addr <= _length-n {
+			_offset := addr - _section.addr
+			return _section.data[_offset : _offset+n], true
+		}
+	}
+
+	return nil, false
+} // read()
+
+// word decodes the pointer-sized word of the given data
+func (i *image) word(data []byte) uint64 {
+	if i.ptr == 4 {
+		return uint64(i.order.Uint32(data))
+	}
+	return i.order.Uint64(data)
+} // word()
+
+// decode returns the string whose header is at the start of the given
+// data, and true, or false if the header does not reference valid UTF-8
+// within the image
+func (i *image) decode(data []byte) (string, bool) {
+	if len(data) < 2*i.ptr {
+		return "", false
+	}
+	_ptr, _len := i.word(data), i.word(data[i.ptr:])
+	if _len == 0 {
+		return "", true
+	}
+	_bytes, _ok := i.read(_ptr, _len)
+	if !_ok || !utf8.Valid(_bytes) {
+		return "", false
+	}
+
+	return string(_bytes), true
+} // decode()
+
+// string returns the string whose header is at the given address, and
+// true, or false if there is no valid string header at the address
+func (i *image) string(addr uint64) (string, bool) {
+	_header, _ok := i.read(addr, uint64(2*i.ptr))
+	if !_ok {
+		return "", false
+	}
+
+	return i.decode(_header)
+} // string()
+
+// pairs returns the longest run of field name and value string pairs,
+// with at least two fields, within the data sections of the image, such as
+// those of the array generated by "gitinfo -X"
+func (i *image) pairs(fields map[string]string) map[string]string {
+	_pairs := make(map[string]string)
+	_stride := 4 * i.ptr
+	for _, _section := range i.sections {
+		_name := strings.TrimLeft(_section.name, "._")
+		if _name != "data" && _name != "noptrdata" {
+			continue
+		}
+
+		_data := _section.data
+		for _offset := 0; _offset+_stride <= len(_data); _offset += i.ptr {
+			// collect the run of pairs starting at this offset
+			_run := make(map[string]string)
+			_next := _offset
+			for ; _next+_stride <= len(_data); _next += _stride {
+				_key, _ok := i.decode(_data[_next:])
+				if !_ok || fields[normalise(_key)] != _key || _key == "" {
+					break
+				}
+				//		- values that are field names suggest this is a list
+				//		  of field names
+				_value, _ok := i.decode(_data[_next+2*i.ptr:])
+				if !_ok || (_value != "" && fields[normalise(_value)] == _value) {
+					break
+				}
+				_run[_key] = _value
+			}
+
+			// is this the longest run?
+			if len(_run) >= 2 && len(_run) > len(_pairs) {
+				_pairs = _run
+			}
+			if _next > _offset {
+				_offset = _next - i.ptr
+			}
+		}
+	}
+
+	return _pairs
+} // pairs()
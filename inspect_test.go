@@ -0,0 +1,71 @@
+package gitinfo_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/denormal/go-gitinfo"
+)
+
+var (
+	// the git information of this test binary, as generated by gitinfo
+	_inspect = [...][2]string{
+		{gitinfo.BRANCH, "inspect"},
+		{gitinfo.COMMIT, "0123456789abcdef0123456789abcdef01234567"},
+		{gitinfo.MODIFIED, "true"},
+	}
+
+	// the git information of this test binary, as set by "-ldflags -X"
+	gitUserEmail = "inspect@example.com"
+)
+
+func TestInspect(t *testing.T) {
+	// ensure the linker retains the git information of the test binary
+	if len(_inspect) == 0 || gitUserEmail == "" {
+		t.Fatal("unexpected empty git information")
+	}
+
+	// inspect the test binary
+	_path, _err := os.Executable()
+	if _err != nil {
+		t.Skipf("unable to determine test binary: %s", _err.Error())
+	}
+	_map, _err := gitinfo.Inspect(_path)
+	if _err != nil {
+		t.Fatalf("unexpected error from Inspect(): %s", _err.Error())
+	}
+	for _, _field := range _inspect {
+		if _map[_field[0]] != _field[1] {
+			t.Fatalf(
+				"unexpected value for %q; expected %q, got %q",
+				_field[0], _field[1], _map[_field[0]],
+			)
+		}
+	}
+	//		- "go test" may strip the symbol table of the test binary
+	_email, _ok := _map[gitinfo.USER_EMAIL]
+	if _ok && _email != gitUserEmail {
+		t.Fatalf(
+			"unexpected value for %q; expected %q, got %q",
+			gitinfo.USER_EMAIL, gitUserEmail, _email,
+		)
+	}
+
+	// files that are not binaries cannot be inspected
+	_dir, _err := ioutil.TempDir("", "")
+	if _err != nil {
+		t.Fatalf("unable to create temporary directory: %s", _err.Error())
+	}
+	defer os.RemoveAll(_dir)
+	_file := filepath.Join(_dir, "binary")
+	_err = ioutil.WriteFile(_file, []byte("not a binary"), 0644)
+	if _err != nil {
+		t.Fatalf("unable to write %s: %s", _file, _err.Error())
+	}
+	_, _err = gitinfo.Inspect(_file)
+	if _err == nil {
+		t.Fatal("expected error from Inspect() for non-binary file")
+	}
+} // TestInspect()
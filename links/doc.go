@@ -0,0 +1,10 @@
+/*
+Package links constructs web URLs for the commits, trees and files of a git
+repository hosted by GitHub, GitLab, Bitbucket, Gitea or Azure DevOps, based
+on the repository's remote URL. Self-hosted instances are recognised by
+their host name where possible, or may be configured explicitly through the
+git configuration of the working copy:
+
+	git config gitinfo.git.example.com.provider gitlab
+*/
+package links
@@ -0,0 +1,10 @@
+package links
+
+import (
+	"errors"
+)
+
+var (
+	MissingRemoteError   = errors.New("unable to determine git remote")
+	UnknownProviderError = errors.New("unknown hosting provider")
+)
@@ -0,0 +1,110 @@
+package links
+
+import (
+	"os"
+	"strings"
+
+	"github.com/denormal/go-gitconfig"
+	"github.com/denormal/go-gittools"
+)
+
+// the default git remote
+const _ORIGIN = "origin"
+
+// New returns the Repository for the git remote of the working copy
+// containing the current process working directory.
+func New() (Repository, error) {
+	return NewWithPath("")
+} // New()
+
+// NewWithPath returns the Repository for the git remote of the working copy
+// containing path. If path is "", NewWithPath examines the current process
+// working directory.
+func NewWithPath(path string) (Repository, error) {
+	var _err error
+
+	// if we have an empty path, then choose the current working directory
+	if path == "" {
+		path, _err = os.Getwd()
+		if _err != nil {
+			return nil, _err
+		}
+	}
+
+	// attempt to load the git configuration
+	_config, _err := gitconfig.NewWithPath(path)
+	if _err != nil {
+		return nil, _err
+	}
+
+	return NewWithConfig(_config)
+} // NewWithPath()
+
+// NewWithConfig returns the Repository for the git remote of the working
+// copy with the given configuration. The remote is the remote of the
+// current branch, if it has one, or "origin". The hosting provider of the
+// remote host is taken from the "gitinfo.<host>.provider" configuration,
+// if set, or is otherwise determined from the host name. If the working copy
+// has no remote, NewWithConfig returns the MissingRemoteError.
+func NewWithConfig(config gitconfig.GitConfig) (Repository, error) {
+	if config == nil || config.Root() == "" {
+		return nil, gittools.MissingWorkingCopyError
+	}
+	_root := config.Root()
+
+	// determine the remote of the current branch
+	_remote := _ORIGIN
+	_branch, _err := gittools.RunInPath(
+		_root, "symbolic-ref", "--quiet", "--short", "HEAD",
+	)
+	if _err == nil {
+		_name := strings.TrimSpace(string(_branch))
+		_value := config.Get("branch." + _name + ".remote")
+		if _name != "" && _value != nil && _value.String() != "" &&
+			_value.String() != "." {
+			_remote = _value.String()
+		}
+	}
+
+	return NewWithRemote(config, _remote)
+} // NewWithConfig()
+
+// NewWithRemote returns the Repository for the named git remote of the
+// working copy with the given configuration. The hosting provider of the
+// remote host is taken from the "gitinfo.<host>.provider" configuration, if
+// set, or is otherwise determined from the host name. If the working copy
+// does not have the named remote, NewWithRemote returns the
+// MissingRemoteError.
+func NewWithRemote(
+	config gitconfig.GitConfig,
+	remote string,
+) (Repository, error) {
+	if config == nil || config.Root() == "" {
+		return nil, gittools.MissingWorkingCopyError
+	} else if remote == "" || strings.HasPrefix(remote, "-") {
+		return nil, MissingRemoteError
+	}
+
+	// extract the remote URL
+	//		- "git remote get-url" applies any "url.<base>.insteadOf" rules
+	_output, _err := gittools.RunInPath(
+		config.Root(), "remote", "get-url", remote,
+	)
+	if _err != nil {
+		return nil, MissingRemoteError
+	}
+	_url := strings.TrimSpace(string(_output))
+
+	// has the provider been configured for the remote host?
+	_host, _err := Host(_url)
+	if _err != nil {
+		return nil, _err
+	}
+	_provider := Provider("")
+	_value := config.Get("gitinfo." + _host + ".provider")
+	if _value != nil {
+		_provider = Provider(strings.ToLower(_value.String()))
+	}
+
+	return ParseWithProvider(_url, _provider)
+} // NewWithRemote()
@@ -0,0 +1,118 @@
+package links_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/denormal/go-gitconfig"
+	"github.com/denormal/go-gitinfo/links"
+	"github.com/denormal/go-gittools"
+)
+
+func TestGit(t *testing.T) {
+	// if we don't have git installed, then skip this test
+	if !gittools.HasGit() {
+		t.Skip("git not installed")
+	}
+
+	_tmp, _err := ioutil.TempDir("", "")
+	if _err != nil {
+		t.Fatalf("unable to create temporary directory: %s", _err.Error())
+	}
+	defer os.RemoveAll(_tmp)
+	_dir, _err := filepath.EvalSymlinks(_tmp)
+	if _err != nil {
+		t.Fatalf("unable to resolve temporary directory: %s", _err.Error())
+	}
+	git(t, _dir, "init", "-q", "-b", "main")
+
+	// without a remote, we cannot construct any links
+	_, _err = links.NewWithPath(_dir)
+	if _err != links.MissingRemoteError {
+		t.Fatalf(
+			"expected %v from NewWithPath(); got %v",
+			links.MissingRemoteError, _err,
+		)
+	}
+
+	// the "origin" remote is used by default
+	git(t, _dir, "remote", "add", "origin", "git@github.com:org/repo.git")
+	git(t, _dir, "remote", "add", "fork", "git@git.example.com:me/repo.git")
+	remote(t, _dir, links.GITHUB, "https://github.com/org/repo")
+
+	// the remote of the current branch takes precedence
+	//		- self-hosted providers must be configured
+	git(t, _dir, "config", "branch.main.remote", "fork")
+	_, _err = links.NewWithPath(_dir)
+	if _err != links.UnknownProviderError {
+		t.Fatalf(
+			"expected %v from NewWithPath(); got %v",
+			links.UnknownProviderError, _err,
+		)
+	}
+	git(t, _dir, "config", "gitinfo.git.example.com.provider", "GitLab")
+	remote(t, _dir, links.GITLAB, "https://git.example.com/me/repo")
+
+	// explicit remotes ignore the remote of the current branch
+	_config, _err := gitconfig.NewWithPath(_dir)
+	if _err != nil {
+		t.Fatalf("unexpected error from NewWithPath(): %s", _err.Error())
+	}
+	_repository, _err := links.NewWithRemote(_config, "origin")
+	if _err != nil {
+		t.Fatalf("unexpected error from NewWithRemote(): %s", _err.Error())
+	} else if _repository.URL() != "https://github.com/org/repo" {
+		t.Fatalf(
+			"unexpected URL; expected %q, got %q",
+			"https://github.com/org/repo", _repository.URL(),
+		)
+	}
+	for _, _remote := range []string{"missing", "--help", ""} {
+		_, _err = links.NewWithRemote(_config, _remote)
+		if _err != links.MissingRemoteError {
+			t.Fatalf(
+				"expected %v from NewWithRemote(%q); got %v",
+				links.MissingRemoteError, _remote, _err,
+			)
+		}
+	}
+
+	// paths outside a working copy report an error
+	_, _err = links.NewWithPath(_tmp + "-missing")
+	if _err == nil {
+		t.Fatal("expected error from NewWithPath() for missing path")
+	}
+} // TestGit()
+
+//
+// helper functions
+//
+
+func remote(t *testing.T, dir string, provider links.Provider, url string) {
+	_repository, _err := links.NewWithPath(dir)
+	if _err != nil {
+		t.Fatalf("unexpected error from NewWithPath(): %s", _err.Error())
+	} else if _repository.Provider() != provider {
+		t.Fatalf(
+			"unexpected provider; expected %q, got %q",
+			provider, _repository.Provider(),
+		)
+	} else if _repository.URL() != url {
+		t.Fatalf(
+			"unexpected URL; expected %q, got %q",
+			url, _repository.URL(),
+		)
+	}
+} // remote()
+
+func git(t *testing.T, dir string, args ...string) string {
+	_output, _err := gittools.RunInPath(dir, args...)
+	if _err != nil {
+		t.Fatalf("git %s: %s", strings.Join(args, " "), _err.Error())
+	}
+
+	return string(_output)
+} // git()
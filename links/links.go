@@ -0,0 +1,355 @@
+package links
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Provider identifies a git hosting provider.
+type Provider string
+
+// the supported hosting providers
+const (
+	AZURE     Provider = "azure"
+	BITBUCKET Provider = "bitbucket"
+	GITEA     Provider = "gitea"
+	GITHUB    Provider = "github"
+	GITLAB    Provider = "gitlab"
+)
+
+// _HOSTS maps the host names of the public hosting services to their
+// providers
+var _HOSTS = map[string]Provider{
+	"bitbucket.org":           BITBUCKET,
+	"codeberg.org":            GITEA,
+	"dev.azure.com":           AZURE,
+	"gitea.com":               GITEA,
+	"github.com":              GITHUB,
+	"gitlab.com":              GITLAB,
+	"ssh.dev.azure.com":       AZURE,
+	"vs-ssh.visualstudio.com": AZURE,
+}
+
+// Repository constructs the web URLs for a hosted git repository.
+type Repository interface {
+	// Provider returns the hosting provider of the repository.
+	Provider() Provider
+
+	// URL returns the web URL of the repository.
+	URL() string
+
+	// Commit returns the web URL of the given commit.
+	Commit(hash string) string
+
+	// Tree returns the web URL of the root tree of the given ref, which may
+	// be a commit hash or branch name.
+	Tree(ref string) string
+
+	// File returns the web URL of the file at the given path, relative to
+	// the repository root, as of the given ref. If line is greater than
+	// zero, the URL references that line of the file.
+	File(ref, path string, line int) string
+
+	// Compare returns the web URL comparing the from and to refs.
+	Compare(from, to string) string
+}
+
+type repository struct {
+	provider Provider
+	url      string
+}
+
+// Parse returns the Repository for the given git remote URL, which may be
+// either a URL (e.g. "https://github.com/org/repo.git") or an scp-like
+// address (e.g. "git@github.com:org/repo.git"). The hosting provider is
+// determined from the remote host name. If the provider cannot be
+// determined, Parse returns the UnknownProviderError.
+func Parse(remote string) (Repository, error) {
+	return ParseWithProvider(remote, "")
+} // Parse()
+
+// ParseWithProvider returns the Repository for the given git remote URL,
+// hosted by the given provider. If provider is "", the provider is
+// determined from the remote host name, as with Parse.
+func ParseWithProvider(remote string, provider Provider) (Repository, error) {
+	_scheme, _host, _path, _err := parse(remote)
+	if _err != nil {
+		return nil, _err
+	}
+
+	// determine the hosting provider
+	if provider == "" {
+		provider = detect(_host, _path)
+		if provider == "" {
+			return nil, UnknownProviderError
+		}
+	}
+	switch provider {
+	case AZURE, BITBUCKET, GITEA, GITHUB, GITLAB:
+	default:
+		return nil, fmt.Errorf("unknown hosting provider %q", provider)
+	}
+
+	// remote repositories are browsed over HTTPS, unless they are cloned
+	// over plain HTTP
+	if _scheme != "http" {
+		_scheme = "https"
+	}
+
+	// Azure DevOps SSH remotes have the form v3/org/project/repo, which is
+	// browsed as https://dev.azure.com/org/project/_git/repo (or
+	// https://org.visualstudio.com/project/_git/repo)
+	if provider == AZURE && strings.HasPrefix(_path, "v3/") {
+		_parts := strings.Split(_path, "/")
+		if len(_parts) != 4 {
+			return nil, fmt.Errorf("invalid Azure DevOps remote %q", remote)
+		}
+		_org, _project, _repo := _parts[1], _parts[2], _parts[3]
+		switch _host {
+		case "ssh.dev.azure.com":
+			_host, _path = "dev.azure.com", _org+"/"+_project+"/_git/"+_repo
+		case "vs-ssh.visualstudio.com":
+			_host, _path = _org+".visualstudio.com", _project+"/_git/"+_repo
+		default:
+			_path = _org + "/" + _project + "/_git/" + _repo
+		}
+	}
+
+	return &repository{
+		provider: provider,
+		url:      _scheme + "://" + _host + "/" + _path,
+	}, nil
+} // ParseWithProvider()
+
+// Host returns the host name of the given git remote URL, without any port,
+// or an error if the remote URL cannot be parsed.
+func Host(remote string) (string, error) {
+	_, _host, _, _err := parse(remote)
+	return hostname(_host), _err
+} // Host()
+
+func (r *repository) Provider() Provider { return r.provider }
+func (r *repository) URL() string        { return r.url }
+
+// Commit returns the web URL of the given commit.
+func (r *repository) Commit(hash string) string {
+	switch r.provider {
+	case BITBUCKET:
+		return r.url + "/commits/" + escape(hash)
+	case GITLAB:
+		return r.url + "/-/commit/" + escape(hash)
+	default:
+		return r.url + "/commit/" + escape(hash)
+	}
+} // Commit()
+
+// Tree returns the web URL of the root tree of the given ref.
+func (r *repository) Tree(ref string) string {
+	switch r.provider {
+	case AZURE:
+		return r.url + "?" + url.Values{"version": {version(ref)}}.Encode()
+	case BITBUCKET:
+		return r.url + "/src/" + escape(ref) + "/"
+	case GITEA:
+		return r.url + "/src/" + gitea(ref)
+	case GITLAB:
+		return r.url + "/-/tree/" + escape(ref)
+	default:
+		return r.url + "/tree/" + escape(ref)
+	}
+} // Tree()
+
+// File returns the web URL of the file at the given path as of the given
+// ref, referencing the given line if it is greater than zero.
+func (r *repository) File(ref, path string, line int) string {
+	path = strings.Trim(path, "/")
+	_line := strconv.Itoa(line)
+
+	switch r.provider {
+	case AZURE:
+		_query := url.Values{
+			"path":    {"/" + path},
+			"version": {version(ref)},
+		}
+		if line > 0 {
+			_query.Set("line", _line)
+			_query.Set("lineEnd", strconv.Itoa(line+1))
+			_query.Set("lineStartColumn", "1")
+			_query.Set("lineEndColumn", "1")
+		}
+		return r.url + "?" + _query.Encode()
+	case BITBUCKET:
+		_url := r.url + "/src/" + escape(ref) + "/" + escape(path)
+		if line > 0 {
+			_url += "#lines-" + _line
+		}
+		return _url
+	}
+
+	// GitHub, GitLab and Gitea share the same line anchors
+	var _url string
+	switch r.provider {
+	case GITEA:
+		_url = r.url + "/src/" + gitea(ref) + "/" + escape(path)
+	case GITLAB:
+		_url = r.url + "/-/blob/" + escape(ref) + "/" + escape(path)
+	default:
+		_url = r.url + "/blob/" + escape(ref) + "/" + escape(path)
+	}
+	if line > 0 {
+		_url += "#L" + _line
+	}
+
+	return _url
+} // File()
+
+// Compare returns the web URL comparing the from and to refs.
+func (r *repository) Compare(from, to string) string {
+	switch r.provider {
+	case AZURE:
+		return r.url + "/branchCompare?" + url.Values{
+			"baseVersion":   {version(from)},
+			"targetVersion": {version(to)},
+		}.Encode()
+	case BITBUCKET:
+		// Bitbucket compares the source (to) with the destination (from)
+		return r.url + "/branches/compare/" +
+			escape(to) + "%0D" + escape(from)
+	case GITLAB:
+		return r.url + "/-/compare/" + escape(from) + "..." + escape(to)
+	default:
+		return r.url + "/compare/" + escape(from) + "..." + escape(to)
+	}
+} // Compare()
+
+//
+// helper functions
+//
+
+// parse extracts the scheme, host and repository path from the given git
+// remote URL, where the path has no leading or trailing slashes, and no
+// ".git" suffix. The host of HTTP and HTTPS remotes retains any port, since
+// the repository is browsed on the same port, while the port of SSH remotes
+// is dropped.
+func parse(remote string) (string, string, string, error) {
+	var _scheme, _host, _path string
+
+	// is this an scp-like address?
+	//		- i.e. [user@]host:path, where there is no slash before the colon
+	_colon := strings.Index(remote, ":")
+	_slash := strings.Index(remote, "/")
+	if !strings.Contains(remote, "://") && _colon > 0 &&
+		(_slash < 0 || _colon < _slash) {
+		_scheme, _host, _path = "ssh", remote[:_colon], remote[_colon+1:]
+		if _at := strings.LastIndex(_host, "@"); _at >= 0 {
+			_host = _host[_at+1:]
+		}
+	} else {
+		_url, _err := url.Parse(remote)
+		if _err != nil {
+			return "", "", "", fmt.Errorf("invalid git remote %q", remote)
+		}
+		_scheme, _host, _path = _url.Scheme, _url.Hostname(), _url.Path
+		if _scheme == "http" || _scheme == "https" {
+			_host = _url.Host
+		}
+	}
+
+	_host = strings.ToLower(_host)
+	_path = strings.TrimSuffix(strings.Trim(_path, "/"), ".git")
+	if _host == "" || _path == "" {
+		return "", "", "", fmt.Errorf("invalid git remote %q", remote)
+	}
+
+	return _scheme, _host, _path, nil
+} // parse()
+
+// detect returns the hosting provider for the given host and repository
+// path, or "" if it cannot be determined
+func detect(host, path string) Provider {
+	host = hostname(host)
+
+	// is this a public hosting service?
+	if _provider, _ok := _HOSTS[host]; _ok {
+		return _provider
+	} else if strings.HasSuffix(host, ".visualstudio.com") {
+		return AZURE
+	}
+
+	// Azure DevOps Server repository paths contain "_git"
+	if strings.Contains("/"+path+"/", "/_git/") {
+		return AZURE
+	}
+
+	// self-hosted services are commonly named for the provider
+	//		- e.g. gitlab.example.com
+	_name := strings.SplitN(host, ".", 2)[0]
+	for _, _provider := range []Provider{BITBUCKET, GITEA, GITHUB, GITLAB} {
+		if _name == string(_provider) {
+			return _provider
+		}
+	}
+
+	return ""
+} // detect()
+
+// hostname returns the given host without any port
+func hostname(host string) string {
+	if _name, _, _err := net.SplitHostPort(host); _err == nil {
+		return _name
+	}
+
+	return host
+} // hostname()
+
+// escape escapes the segments of the given slash-separated path for use
+// within a URL path
+func escape(path string) string {
+	_segments := strings.Split(path, "/")
+	for _i, _segment := range _segments {
+		_segments[_i] = url.PathEscape(_segment)
+	}
+
+	return strings.Join(_segments, "/")
+} // escape()
+
+// hash returns true if ref is a full commit hash
+func hash(ref string) bool {
+	if len(ref) != 40 && len(ref) != 64 {
+		return false
+	}
+	for _i := 0; _i < len(ref); _i++ {
+		_c := ref[_i]
+		if !(_c >= '0' && _c <= '9' || _c >= 'a' && _c <= 'f') {
+			return false
+		}
+	}
+
+	return true
+} // hash()
+
+// version returns the Azure DevOps version descriptor for the given ref,
+// which is either a commit hash or a branch name
+func version(ref string) string {
+	if hash(ref) {
+		return "GC" + ref
+	}
+
+	return "GB" + ref
+} // version()
+
+// gitea returns the Gitea source path for the given ref, which is either a
+// commit hash or a branch name
+func gitea(ref string) string {
+	if hash(ref) {
+		return "commit/" + escape(ref)
+	}
+
+	return "branch/" + escape(ref)
+} // gitea()
+
+// ensure repository implements the Repository interface
+var _ Repository = &repository{}
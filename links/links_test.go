@@ -0,0 +1,191 @@
+package links_test
+
+import (
+	"testing"
+
+	"github.com/denormal/go-gitinfo/links"
+)
+
+const (
+	_HASH = "0123456789abcdef0123456789abcdef01234567"
+	_FILE = "dir/file name.go"
+)
+
+type _test struct {
+	remote   string
+	provider links.Provider
+	url      string
+	commit   string
+	tree     string
+	file     string
+	line     string
+	compare  string
+}
+
+var _TESTS = []_test{
+	{
+		"git@github.com:org/repo.git", links.GITHUB,
+		"https://github.com/org/repo",
+		"https://github.com/org/repo/commit/" + _HASH,
+		"https://github.com/org/repo/tree/main",
+		"https://github.com/org/repo/blob/main/dir/file%20name.go",
+		"https://github.com/org/repo/blob/" + _HASH + "/dir/file%20name.go#L12",
+		"https://github.com/org/repo/compare/v1.0.0...main",
+	},
+	{
+		"https://gitlab.com/group/sub/repo.git", links.GITLAB,
+		"https://gitlab.com/group/sub/repo",
+		"https://gitlab.com/group/sub/repo/-/commit/" + _HASH,
+		"https://gitlab.com/group/sub/repo/-/tree/main",
+		"https://gitlab.com/group/sub/repo/-/blob/main/dir/file%20name.go",
+		"https://gitlab.com/group/sub/repo/-/blob/" + _HASH +
+			"/dir/file%20name.go#L12",
+		"https://gitlab.com/group/sub/repo/-/compare/v1.0.0...main",
+	},
+	{
+		"ssh://git@bitbucket.org/team/repo.git", links.BITBUCKET,
+		"https://bitbucket.org/team/repo",
+		"https://bitbucket.org/team/repo/commits/" + _HASH,
+		"https://bitbucket.org/team/repo/src/main/",
+		"https://bitbucket.org/team/repo/src/main/dir/file%20name.go",
+		"https://bitbucket.org/team/repo/src/" + _HASH +
+			"/dir/file%20name.go#lines-12",
+		"https://bitbucket.org/team/repo/branches/compare/main%0Dv1.0.0",
+	},
+	{
+		"https://codeberg.org/org/repo", links.GITEA,
+		"https://codeberg.org/org/repo",
+		"https://codeberg.org/org/repo/commit/" + _HASH,
+		"https://codeberg.org/org/repo/src/branch/main",
+		"https://codeberg.org/org/repo/src/branch/main/dir/file%20name.go",
+		"https://codeberg.org/org/repo/src/commit/" + _HASH +
+			"/dir/file%20name.go#L12",
+		"https://codeberg.org/org/repo/compare/v1.0.0...main",
+	},
+	{
+		"git@ssh.dev.azure.com:v3/org/project/repo", links.AZURE,
+		"https://dev.azure.com/org/project/_git/repo",
+		"https://dev.azure.com/org/project/_git/repo/commit/" + _HASH,
+		"https://dev.azure.com/org/project/_git/repo?version=GBmain",
+		"https://dev.azure.com/org/project/_git/repo" +
+			"?path=%2Fdir%2Ffile+name.go&version=GBmain",
+		"https://dev.azure.com/org/project/_git/repo" +
+			"?line=12&lineEnd=13&lineEndColumn=1&lineStartColumn=1" +
+			"&path=%2Fdir%2Ffile+name.go&version=GC" + _HASH,
+		"https://dev.azure.com/org/project/_git/repo/branchCompare" +
+			"?baseVersion=GBv1.0.0&targetVersion=GBmain",
+	},
+}
+
+// _REMOTES maps remote URLs to their expected provider and web URL
+var _REMOTES = map[string][2]string{
+	"https://github.com/org/repo":                  {"github", "https://github.com/org/repo"},
+	"https://user@github.com/org/repo.git/":        {"github", "https://github.com/org/repo"},
+	"ssh://git@github.com:22/org/repo.git":         {"github", "https://github.com/org/repo"},
+	"git@gitlab.example.com:group/repo.git":        {"gitlab", "https://gitlab.example.com/group/repo"},
+	"http://gitea.example.com/org/repo.git":        {"gitea", "http://gitea.example.com/org/repo"},
+	"https://org@dev.azure.com/org/project/_git/r": {"azure", "https://dev.azure.com/org/project/_git/r"},
+	"https://org.visualstudio.com/project/_git/r":  {"azure", "https://org.visualstudio.com/project/_git/r"},
+	"org@vs-ssh.visualstudio.com:v3/org/project/r": {"azure", "https://org.visualstudio.com/project/_git/r"},
+	"https://tfs.example.com/tfs/coll/proj/_git/r": {"azure", "https://tfs.example.com/tfs/coll/proj/_git/r"},
+	"https://gitlab.example.com:8443/group/r.git":  {"gitlab", "https://gitlab.example.com:8443/group/r"},
+	"http://github.com:80/org/repo":                {"github", "http://github.com:80/org/repo"},
+	"git@git.example.com:org/repo.git":             {"", ""},
+	"/path/to/repo.git":                            {"", ""},
+	"":                                             {"", ""},
+}
+
+func TestLinks(t *testing.T) {
+	for _, _test := range _TESTS {
+		_repository, _err := links.Parse(_test.remote)
+		if _err != nil {
+			t.Fatalf("%s: unexpected error: %s", _test.remote, _err.Error())
+		}
+
+		for _, _check := range [][3]string{
+			{"Provider()", string(_test.provider), string(_repository.Provider())},
+			{"URL()", _test.url, _repository.URL()},
+			{"Commit()", _test.commit, _repository.Commit(_HASH)},
+			{"Tree()", _test.tree, _repository.Tree("main")},
+			{"File()", _test.file, _repository.File("main", _FILE, 0)},
+			{"File()", _test.line, _repository.File(_HASH, "/"+_FILE, 12)},
+			{"Compare()", _test.compare, _repository.Compare("v1.0.0", "main")},
+		} {
+			if _check[1] != _check[2] {
+				t.Fatalf(
+					"%s: unexpected %s; expected %q, got %q",
+					_test.remote, _check[0], _check[1], _check[2],
+				)
+			}
+		}
+	}
+} // TestLinks()
+
+func TestParse(t *testing.T) {
+	for _remote, _expected := range _REMOTES {
+		_repository, _err := links.Parse(_remote)
+		if _expected[0] == "" {
+			if _err == nil {
+				t.Fatalf(
+					"%q: expected error; got %q",
+					_remote, _repository.URL(),
+				)
+			}
+			continue
+		} else if _err != nil {
+			t.Fatalf("%q: unexpected error: %s", _remote, _err.Error())
+		}
+
+		if string(_repository.Provider()) != _expected[0] {
+			t.Fatalf(
+				"%q: unexpected provider; expected %q, got %q",
+				_remote, _expected[0], _repository.Provider(),
+			)
+		} else if _repository.URL() != _expected[1] {
+			t.Fatalf(
+				"%q: unexpected URL; expected %q, got %q",
+				_remote, _expected[1], _repository.URL(),
+			)
+		}
+	}
+
+	// unknown hosts require an explicit provider
+	_remote := "git@git.example.com:org/repo.git"
+	_, _err := links.Parse(_remote)
+	if _err != links.UnknownProviderError {
+		t.Fatalf(
+			"%q: expected %v; got %v",
+			_remote, links.UnknownProviderError, _err,
+		)
+	}
+	_repository, _err := links.ParseWithProvider(_remote, links.GITEA)
+	if _err != nil {
+		t.Fatalf("%q: unexpected error: %s", _remote, _err.Error())
+	} else if _repository.Commit(_HASH) !=
+		"https://git.example.com/org/repo/commit/"+_HASH {
+		t.Fatalf(
+			"%q: unexpected commit URL %q",
+			_remote, _repository.Commit(_HASH),
+		)
+	}
+
+	// the port of HTTP and HTTPS remotes is retained
+	_remote = "https://git.example.com:8443/group/repo.git"
+	_repository, _err = links.ParseWithProvider(_remote, links.GITLAB)
+	if _err != nil {
+		t.Fatalf("%q: unexpected error: %s", _remote, _err.Error())
+	} else if _repository.URL() != "https://git.example.com:8443/group/repo" {
+		t.Fatalf("%q: unexpected URL %q", _remote, _repository.URL())
+	} else if _host, _ := links.Host(_remote); _host != "git.example.com" {
+		t.Fatalf(
+			"%q: unexpected host; expected %q, got %q",
+			_remote, "git.example.com", _host,
+		)
+	}
+
+	// unknown providers are rejected
+	_, _err = links.ParseWithProvider(_remote, links.Provider("unknown"))
+	if _err == nil {
+		t.Fatalf("%q: expected error for unknown provider", _remote)
+	}
+} // TestParse()
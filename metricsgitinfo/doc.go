@@ -0,0 +1,22 @@
+/*
+Package metricsgitinfo publishes git information as metrics. Publish
+exposes the fields of a GitInfo through expvar, while Prometheus renders a
+build_info gauge in the Prometheus text exposition format, labelled with
+the branch, commit, modified state and version of the build, without
+depending on the Prometheus client library:
+
+	_info, _ := gitinfo.Here()
+	metricsgitinfo.Publish("git", _info)
+
+Export writes the same gauge to an io.Writer, such as the file read by the
+node-exporter textfile collector:
+
+	_file, _ := os.Create("/var/lib/node_exporter/server.prom.tmp")
+	metricsgitinfo.Export(_file, _info)
+	_file.Close()
+	os.Rename(_file.Name(), "/var/lib/node_exporter/server.prom")
+
+Note that importing this package registers the expvar handler with
+http.DefaultServeMux.
+*/
+package metricsgitinfo
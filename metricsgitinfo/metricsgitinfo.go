@@ -0,0 +1,100 @@
+package metricsgitinfo
+
+import (
+	"expvar"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"strconv"
+	"strings"
+
+	"github.com/denormal/go-gitinfo"
+)
+
+// NAME is the name of the Prometheus gauge rendered by Prometheus
+const NAME = "build_info"
+
+// the labels of the Prometheus gauge, in the order they are rendered
+var _LABELS = []string{"branch", "commit", "modified", "version"}
+
+// Publish publishes the fields of the given GitInfo as the expvar variable
+// name, as a JSON object of field names to values. The fields are
+// determined once, when Publish is called. As with expvar.Publish, Publish
+// panics if name is already published.
+func Publish(name string, gi gitinfo.GitInfo) {
+	_map := gi.Map()
+	expvar.Publish(name, expvar.Func(func() interface{} { return _map }))
+} // Publish()
+
+// Prometheus returns the NAME gauge of the given GitInfo in the Prometheus
+// text exposition format, with a constant value of 1, and labels of the
+// branch, commit, modified state and version of the build. The version is
+// the pseudo-version of the commit, or the version of the main module
+// recorded by the go tool if the pseudo-version is not known. Labels whose
+// values are not known are rendered as empty strings.
+func Prometheus(gi gitinfo.GitInfo) string {
+	_labels := labels(gi)
+	_pairs := make([]string, 0, len(_LABELS))
+	for _, _label := range _LABELS {
+		_pairs = append(_pairs, fmt.Sprintf("%s=\"%s\"",
+			_label, escape(_labels[_label]),
+		))
+	}
+
+	return fmt.Sprintf(
+		"# HELP %s Git information of the build.\n"+
+			"# TYPE %s gauge\n"+
+			"%s{%s} 1\n",
+		NAME, NAME, NAME, strings.Join(_pairs, ","),
+	)
+} // Prometheus()
+
+// Export writes the NAME gauge of the given GitInfo, as returned by
+// Prometheus, to the writer w, such as a file read by the node-exporter
+// textfile collector. An error is returned if the gauge cannot be written.
+func Export(w io.Writer, gi gitinfo.GitInfo) error {
+	_, _err := io.WriteString(w, Prometheus(gi))
+	return _err
+} // Export()
+
+// labels returns the values of the gauge labels for the given GitInfo
+func labels(gi gitinfo.GitInfo) map[string]string {
+	// determine only the labelled fields, rather than the fields of Map(),
+	// as the gauge may be rendered for every scrape
+	var (
+		_branch, _   = gi.Branch()
+		_commit      = ""
+		_modified, _ = gi.Modified()
+	)
+	if _c, _err := gi.Commit(); _err == nil && _c != nil {
+		_commit = _c.String()
+	}
+
+	// use the pseudo-version of the commit if we have one, otherwise fall
+	// back to the version of the main module
+	//		- ignore the placeholder version of development builds
+	_version, _ := gi.PseudoVersion()
+	if _version == "" {
+		_build, _ok := debug.ReadBuildInfo()
+		if _ok && _build.Main.Version != "(devel)" {
+			_version = _build.Main.Version
+		}
+	}
+
+	return map[string]string{
+		"branch":   _branch,
+		"commit":   _commit,
+		"modified": strconv.FormatBool(_modified),
+		"version":  _version,
+	}
+} // labels()
+
+// escape returns the given label value with backslashes, double quotes and
+// newlines escaped, as required by the Prometheus text exposition format
+func escape(value string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"\n", `\n`,
+	).Replace(value)
+} // escape()
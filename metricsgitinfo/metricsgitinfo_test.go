@@ -0,0 +1,95 @@
+package metricsgitinfo_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"runtime/debug"
+	"testing"
+
+	"github.com/denormal/go-gitinfo"
+	"github.com/denormal/go-gitinfo/metricsgitinfo"
+)
+
+// the git information published by the tests
+var _MAP = map[string]string{
+	gitinfo.BRANCH:   `feature/"quoted"\path`,
+	gitinfo.COMMIT:   "0123456789abcdef0123456789abcdef01234567",
+	gitinfo.MODIFIED: "true",
+}
+
+func TestPublish(t *testing.T) {
+	_info := gitinfo.Build(_MAP)
+	metricsgitinfo.Publish("git", _info)
+
+	// is the git information published as a JSON object?
+	_var := expvar.Get("git")
+	if _var == nil {
+		t.Fatalf("git information not published")
+	}
+	_got := make(map[string]string)
+	_err := json.Unmarshal([]byte(_var.String()), &_got)
+	if _err != nil {
+		t.Fatalf("unable to decode published value: %s", _err.Error())
+	}
+	for _k, _v := range _info.Map() {
+		if _got[_k] != _v {
+			t.Fatalf(
+				"unexpected value for %q; expected %q, got %q", _k, _v, _got[_k],
+			)
+		}
+	}
+} // TestPublish()
+
+func TestPrometheus(t *testing.T) {
+	// the version falls back to that of the main module
+	_version := ""
+	if _build, _ok := debug.ReadBuildInfo(); _ok {
+		if _build.Main.Version != "(devel)" {
+			_version = _build.Main.Version
+		}
+	}
+
+	_expected := fmt.Sprintf(
+		"# HELP build_info Git information of the build.\n"+
+			"# TYPE build_info gauge\n"+
+			`build_info{branch="feature/\"quoted\"\\path",`+
+			`commit="0123456789abcdef0123456789abcdef01234567",`+
+			`modified="true",version="%s"} 1`+"\n",
+		_version,
+	)
+	//		- the gauge must not determine all fields of Map()
+	_info := &labelled{gitinfo.Build(_MAP), t}
+	_got := metricsgitinfo.Prometheus(_info)
+	if _got != _expected {
+		t.Fatalf("unexpected gauge; expected %q, got %q", _expected, _got)
+	}
+
+	// does the exporter write the same gauge?
+	_buffer := new(bytes.Buffer)
+	_err := metricsgitinfo.Export(_buffer, _info)
+	if _err != nil {
+		t.Fatalf("unexpected error from Export(): %s", _err.Error())
+	} else if _buffer.String() != _expected {
+		t.Fatalf(
+			"unexpected export; expected %q, got %q",
+			_expected, _buffer.String(),
+		)
+	}
+} // TestPrometheus()
+
+//
+// helper types
+//
+
+// labelled is a GitInfo that fails the test if Map() is called
+type labelled struct {
+	gitinfo.GitInfo
+	t *testing.T
+}
+
+func (l *labelled) Map() map[string]string {
+	l.t.Fatalf("unexpected call to Map()")
+	return nil
+} // Map()
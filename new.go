@@ -36,7 +36,25 @@ func NewWithPath(path string) (GitInfo, error) {
 	// create the GitInfo instance
 	_info := &gitinfo{
 		config: _config,
+		counts: &counts{},
 	}
 
 	return _info, nil
 } // NewWithPath()
+
+// NewWithCI returns the GitInfo instance for the given path, as with
+// NewWithOptions, that recognises the continuous integration environment
+// of the current process. If the commit being built by CI is the HEAD commit
+// of the working copy, CI() returns the CI environment, Branch() reports the
+// branch being built for a detached HEAD, and Map() includes the ci.*
+// fields. Otherwise, such as for other working copies of a CI build, the CI
+// environment is ignored.
+func NewWithCI(path string, opts ModifiedOptions) (GitInfo, error) {
+	_info, _err := NewWithOptions(path, opts)
+	if _err != nil {
+		return nil, _err
+	}
+	_info.(*gitinfo).ci = true
+
+	return _info, nil
+} // NewWithCI()
@@ -0,0 +1,299 @@
+package gitinfo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/denormal/go-gittools"
+)
+
+// _PATCH is the marker prefixing encoded patches, which are of the form
+// "gitinfo-patch:<length>:<base64>", so that they may be located within
+// compiled binaries
+const _PATCH = "gitinfo-patch:"
+
+// MissingPatchError is returned when an encoded patch cannot be found.
+var MissingPatchError = errors.New("no gitinfo patch found")
+
+// Patch represents the uncommitted modifications of a working copy, so that
+// the source of a build from a modified working copy may be reproduced.
+type Patch interface {
+	// Commit returns the commit the patch applies to.
+	Commit() string
+
+	// Diff returns the binary patch of the staged and unstaged changes to
+	// the tracked files of the working copy, as produced by
+	// "git diff --binary HEAD".
+	Diff() []byte
+
+	// Untracked returns the contents of the untracked files of the working
+	// copy, keyed by their slash-separated paths relative to the working
+	// copy root.
+	Untracked() map[string][]byte
+
+	// Modes returns the modes of the untracked files of the working copy,
+	// keyed by their slash-separated paths relative to the working copy
+	// root; os.ModeSymlink for symbolic links, whose contents are their
+	// targets, 0755 for executable files, and 0644 for other files.
+	Modes() map[string]os.FileMode
+
+	// Encode returns the patch encoded as a compressed string, suitable for
+	// embedding in source code and compiled binaries. See ParsePatch and
+	// FindPatch for decoding the patch.
+	Encode() string
+
+	// Apply applies the patch to the working copy rooted at dir, which is
+	// expected to be a checkout of the patch commit, applying the diff and
+	// creating the untracked files. An error is returned if an untracked
+	// file would be created outside dir, or beneath a symbolic link.
+	Apply(dir string) error
+}
+
+type patch struct {
+	CommitHash     string            `json:"commit"`
+	DiffBytes      []byte            `json:"diff,omitempty"`
+	UntrackedFiles map[string][]byte `json:"untracked,omitempty"`
+
+	// the git modes of the untracked files, other than regular files
+	UntrackedModes map[string]string `json:"modes,omitempty"`
+}
+
+// Patch returns the Patch of the uncommitted modifications of the working
+// copy, relative to its HEAD commit, including the contents of untracked
+// files. Only the modifications excluded by the options given to
+// NewWithOptions are omitted from the patch. An error is returned if the
+// GitInfo instance was initialised for a path not within a working copy, the
+// working copy has no commits, or there is a problem extracting the
+// modifications.
+func (g *gitinfo) Patch() (Patch, error) {
+	_commit, _err := g.Commit()
+	if _err != nil {
+		return nil, _err
+	} else if _commit == nil {
+		return nil, MissingWorkingCopyError
+	}
+
+	// the patch ignores the IGNORE file, so that the source may be
+	// reproduced exactly
+	_diff, _paths, _err := g.dirty(g.options)
+	if _err != nil {
+		return nil, _err
+	}
+	_untracked := make(map[string][]byte, len(_paths))
+	_modes := make(map[string]string)
+	for _, _path := range _paths {
+		_untracked[_path], _err = untracked(g.Root(), _path)
+		if _err != nil {
+			return nil, _err
+		}
+		_mode, _err := mode(g.Root(), _path)
+		if _err != nil {
+			return nil, _err
+		} else if _mode != _MODE_FILE {
+			_modes[_path] = _mode
+		}
+	}
+
+	return &patch{
+		CommitHash:     _commit.String(),
+		DiffBytes:      _diff,
+		UntrackedFiles: _untracked,
+		UntrackedModes: _modes,
+	}, nil
+} // Patch()
+
+// ParsePatch returns the Patch represented by the given encoded string, as
+// returned by Patch.Encode(). An error is returned if the string is not a
+// valid encoded patch.
+func ParsePatch(s string) (Patch, error) {
+	if !strings.HasPrefix(s, _PATCH) {
+		return nil, MissingPatchError
+	}
+
+	// extract the encoded length
+	_parts := strings.SplitN(strings.TrimPrefix(s, _PATCH), ":", 2)
+	if len(_parts) != 2 {
+		return nil, fmt.Errorf("invalid gitinfo patch")
+	}
+	_length, _err := strconv.Atoi(_parts[0])
+	if _err != nil || _length < 0 || _length > len(_parts[1]) {
+		return nil, fmt.Errorf("invalid gitinfo patch length")
+	}
+
+	// decode the patch
+	_bytes, _err := base64.StdEncoding.DecodeString(_parts[1][:_length])
+	if _err != nil {
+		return nil, fmt.Errorf("invalid gitinfo patch: %s", _err.Error())
+	}
+	_reader, _err := gzip.NewReader(bytes.NewReader(_bytes))
+	if _err != nil {
+		return nil, fmt.Errorf("invalid gitinfo patch: %s", _err.Error())
+	}
+	_patch := &patch{}
+	_err = json.NewDecoder(_reader).Decode(_patch)
+	if _err != nil {
+		return nil, fmt.Errorf("invalid gitinfo patch: %s", _err.Error())
+	} else if _patch.CommitHash == "" {
+		return nil, fmt.Errorf("invalid gitinfo patch: missing commit")
+	}
+
+	return _patch, nil
+} // ParsePatch()
+
+// FindPatch returns the first valid encoded Patch found within the given
+// data, such as the contents of a compiled binary. If no patch is found,
+// FindPatch returns the MissingPatchError.
+func FindPatch(data []byte) (Patch, error) {
+	_marker := []byte(_PATCH)
+	for _offset := 0; ; {
+		_i := bytes.Index(data[_offset:], _marker)
+		if _i < 0 {
+			return nil, MissingPatchError
+		}
+		_offset += _i
+
+		_patch, _err := ParsePatch(string(data[_offset:]))
+		if _err == nil {
+			return _patch, nil
+		}
+		_offset += len(_marker)
+	}
+} // FindPatch()
+
+func (p *patch) Commit() string               { return p.CommitHash }
+func (p *patch) Diff() []byte                 { return p.DiffBytes }
+func (p *patch) Untracked() map[string][]byte { return p.UntrackedFiles }
+
+// Modes returns the modes of the untracked files.
+func (p *patch) Modes() map[string]os.FileMode {
+	_modes := make(map[string]os.FileMode, len(p.UntrackedFiles))
+	for _path, _ := range p.UntrackedFiles {
+		switch p.UntrackedModes[_path] {
+		case _MODE_SYMLINK:
+			_modes[_path] = os.ModeSymlink
+		case _MODE_EXECUTABLE:
+			_modes[_path] = 0755
+		default:
+			_modes[_path] = 0644
+		}
+	}
+
+	return _modes
+} // Modes()
+
+// Encode returns the patch encoded as a compressed string.
+func (p *patch) Encode() string {
+	// gzip output is deterministic for the same input, and JSON encodes
+	// map keys in sorted order, so the encoding is reproducible
+	var _buffer bytes.Buffer
+	_writer := gzip.NewWriter(&_buffer)
+	json.NewEncoder(_writer).Encode(p)
+	_writer.Close()
+
+	_encoded := base64.StdEncoding.EncodeToString(_buffer.Bytes())
+	return _PATCH + strconv.Itoa(len(_encoded)) + ":" + _encoded
+} // Encode()
+
+// Apply applies the patch to the working copy rooted at dir.
+func (p *patch) Apply(dir string) error {
+	// validate the untracked files
+	//		- untracked files must not be created beneath symbolic links,
+	//		  which may lead outside the working copy
+	_modes := p.Modes()
+	_paths := make([]string, 0, len(p.UntrackedFiles))
+	_links := make(map[string]bool)
+	for _path, _ := range p.UntrackedFiles {
+		_paths = append(_paths, _path)
+		if _modes[_path] == os.ModeSymlink {
+			_links[path.Clean(_path)] = true
+		}
+	}
+	sort.Strings(_paths)
+	for _, _path := range _paths {
+		// ensure the path does not escape the working copy
+		_clean := path.Clean(_path)
+		if path.IsAbs(_clean) || _clean == ".." ||
+			strings.HasPrefix(_clean, "../") {
+			return fmt.Errorf("invalid untracked path %q", _path)
+		}
+		_parts := strings.Split(_clean, "/")
+		for _i := 1; _i < len(_parts); _i++ {
+			if _links[strings.Join(_parts[:_i], "/")] {
+				return fmt.Errorf("invalid untracked path %q", _path)
+			}
+		}
+	}
+	// apply the diff of the tracked files
+	//		- "git apply" reads the patch from a file
+	if len(p.DiffBytes) != 0 {
+		_file, _err := ioutil.TempFile("", "gitinfo-patch-")
+		if _err != nil {
+			return _err
+		}
+		defer os.Remove(_file.Name())
+		_, _err = _file.Write(p.DiffBytes)
+		if _err == nil {
+			_err = _file.Close()
+		}
+		if _err != nil {
+			return _err
+		}
+
+		_, _err = gittools.RunInPath(dir, "apply", "--binary", _file.Name())
+		if _err != nil {
+			return _err
+		}
+	}
+
+	// create the untracked files
+	for _, _path := range _paths {
+		// ensure the path does not pass through a symbolic link of the
+		// working copy
+		_clean := path.Clean(_path)
+		_parts := strings.Split(_clean, "/")
+		for _i := 1; _i <= len(_parts); _i++ {
+			_info, _err := os.Lstat(filepath.Join(
+				dir, filepath.FromSlash(strings.Join(_parts[:_i], "/")),
+			))
+			if _err == nil && _info.Mode()&os.ModeSymlink != 0 {
+				return fmt.Errorf("invalid untracked path %q", _path)
+			}
+		}
+
+		//		- restore symbolic links and executable files
+		_file := filepath.Join(dir, filepath.FromSlash(_clean))
+		_err := os.MkdirAll(filepath.Dir(_file), 0755)
+		if _err != nil {
+			return _err
+		}
+		_content := p.UntrackedFiles[_path]
+		switch _mode := _modes[_path]; _mode {
+		case os.ModeSymlink:
+			_err = os.Symlink(filepath.FromSlash(string(_content)), _file)
+		default:
+			_err = ioutil.WriteFile(_file, _content, _mode)
+			if _err == nil {
+				_err = os.Chmod(_file, _mode)
+			}
+		}
+		if _err != nil {
+			return _err
+		}
+	}
+
+	return nil
+} // Apply()
+
+// ensure patch implements the Patch interface
+var _ Patch = &patch{}
@@ -0,0 +1,295 @@
+package gitinfo_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/denormal/go-gitinfo"
+	"github.com/denormal/go-gittools"
+)
+
+func TestPatch(t *testing.T) {
+	// if we don't have git installed, then skip this test
+	if !gittools.HasGit() {
+		t.Skip("git not installed")
+	}
+
+	_dir := repository(t)
+	defer os.RemoveAll(_dir)
+	write := func(name string, content []byte) {
+		_path := filepath.Join(_dir, filepath.FromSlash(name))
+		_err := os.MkdirAll(filepath.Dir(_path), 0755)
+		if _err == nil {
+			_err = ioutil.WriteFile(_path, content, 0644)
+		}
+		if _err != nil {
+			t.Fatalf("unable to write %s: %s", name, _err.Error())
+		}
+	}
+	write("text", []byte("one\ntwo\n"))
+	write("binary", []byte{0, 1, 2, 3})
+	write("removed", []byte("removed\n"))
+	_commit := commit(t, _dir, "2024-01-01T00:00:00Z", "initial")
+
+	// modify the working copy
+	write("text", []byte("one\nthree\n"))
+	write("binary", []byte{3, 2, 1, 0})
+	git(t, _dir, "add", "binary")
+	git(t, _dir, "rm", "-q", "removed")
+	write("sub/untracked", []byte("untracked\n"))
+
+	_info, _err := gitinfo.NewWithPath(_dir)
+	if _err != nil {
+		t.Fatalf("unexpected error from NewWithPath(): %s", _err.Error())
+	}
+	_patch, _err := _info.Patch()
+	if _err != nil {
+		t.Fatalf("unexpected error from Patch(): %s", _err.Error())
+	} else if _patch.Commit() != _commit {
+		t.Fatalf(
+			"unexpected patch commit; expected %q, got %q",
+			_commit, _patch.Commit(),
+		)
+	} else if len(_patch.Untracked()) != 1 ||
+		string(_patch.Untracked()["sub/untracked"]) != "untracked\n" {
+		t.Fatalf("unexpected untracked files: %v", _patch.Untracked())
+	}
+
+	// the encoded patch should be found amongst other data
+	//		- decoy markers should be ignored
+	_encoded := _patch.Encode()
+	if _encoded != _patch.Encode() {
+		t.Fatal("unexpected non-deterministic patch encoding")
+	}
+	_data := []byte("gitinfo-patch:\x00gitinfo-patch:1:A" + _encoded + "ABC")
+	_found, _err := gitinfo.FindPatch(_data)
+	if _err != nil {
+		t.Fatalf("unexpected error from FindPatch(): %s", _err.Error())
+	} else if _found.Encode() != _encoded {
+		t.Fatal("unexpected patch from FindPatch()")
+	}
+	_, _err = gitinfo.FindPatch([]byte("no patch here"))
+	if _err != gitinfo.MissingPatchError {
+		t.Fatalf(
+			"unexpected error from FindPatch(); expected %v, got %v",
+			gitinfo.MissingPatchError, _err,
+		)
+	}
+
+	// the patch should survive the build information
+	_built, _err := gitinfo.Build(map[string]string{
+		gitinfo.COMMIT:         _commit,
+		gitinfo.MODIFIED_PATCH: _encoded,
+	}).Patch()
+	if _err != nil {
+		t.Fatalf("unexpected error from Build().Patch(): %s", _err.Error())
+	} else if _built.Encode() != _encoded {
+		t.Fatal("unexpected patch from Build().Patch()")
+	}
+	_, _err = gitinfo.Build(nil).Patch()
+	if _err != gitinfo.MissingPatchError {
+		t.Fatalf(
+			"unexpected error from Build().Patch(); expected %v, got %v",
+			gitinfo.MissingPatchError, _err,
+		)
+	}
+
+	// applying the patch to a worktree of the commit should reproduce the
+	// modified working copy
+	_worktree := _dir + "-worktree"
+	defer os.RemoveAll(_worktree)
+	git(t, _dir, "worktree", "add", "-q", "--detach", _worktree, _commit)
+	_err = _found.Apply(_worktree)
+	if _err != nil {
+		t.Fatalf("unexpected error from Apply(): %s", _err.Error())
+	}
+	_expected, _err := _info.DirtyHash()
+	if _err != nil {
+		t.Fatalf("unexpected error from DirtyHash(): %s", _err.Error())
+	}
+	_hash := dirty(t, _worktree)
+	if _hash != _expected {
+		t.Fatalf(
+			"unexpected reproduced dirty hash; expected %q, got %q",
+			_expected, _hash,
+		)
+	}
+} // TestPatch()
+
+func TestPatchModes(t *testing.T) {
+	// if we don't have git installed, then skip this test
+	if !gittools.HasGit() {
+		t.Skip("git not installed")
+	} else if runtime.GOOS == "windows" {
+		t.Skip("symbolic links and executable files not supported")
+	}
+
+	_dir := repository(t)
+	defer os.RemoveAll(_dir)
+	_err := ioutil.WriteFile(filepath.Join(_dir, "text"), []byte("one\n"), 0644)
+	if _err != nil {
+		t.Fatalf("unable to write text: %s", _err.Error())
+	}
+	_commit := commit(t, _dir, "2024-01-01T00:00:00Z", "initial")
+
+	// modify the working copy, with a diff driver that must not be applied
+	// to the patch
+	git(t, _dir, "config", "diff.upper.textconv", "sed s/o/O/")
+	_err = ioutil.WriteFile(
+		filepath.Join(_dir, ".git", "info", "attributes"),
+		[]byte("text diff=upper\n"), 0644,
+	)
+	if _err == nil {
+		_err = ioutil.WriteFile(
+			filepath.Join(_dir, "text"), []byte("one\ntwo\n"), 0644,
+		)
+	}
+	if _err == nil {
+		_err = ioutil.WriteFile(
+			filepath.Join(_dir, "run.sh"), []byte("#!/bin/sh\n"), 0755,
+		)
+	}
+	if _err == nil {
+		_err = os.Symlink("run.sh", filepath.Join(_dir, "link"))
+	}
+	if _err != nil {
+		t.Fatalf("unable to modify working copy: %s", _err.Error())
+	}
+
+	_info, _err := gitinfo.NewWithPath(_dir)
+	if _err != nil {
+		t.Fatalf("unexpected error from NewWithPath(): %s", _err.Error())
+	}
+	_patch, _err := _info.Patch()
+	if _err != nil {
+		t.Fatalf("unexpected error from Patch(): %s", _err.Error())
+	}
+	for _path, _mode := range map[string]os.FileMode{
+		"link":   os.ModeSymlink,
+		"run.sh": 0755,
+	} {
+		if _patch.Modes()[_path] != _mode {
+			t.Fatalf(
+				"unexpected mode for %s; expected %v, got %v",
+				_path, _mode, _patch.Modes()[_path],
+			)
+		}
+	}
+
+	// does the encoded patch reproduce the working copy?
+	_decoded, _err := gitinfo.ParsePatch(_patch.Encode())
+	if _err != nil {
+		t.Fatalf("unexpected error from ParsePatch(): %s", _err.Error())
+	}
+	_worktree := _dir + "-worktree"
+	defer os.RemoveAll(_worktree)
+	git(t, _dir, "worktree", "add", "-q", "--detach", _worktree, _commit)
+	_err = _decoded.Apply(_worktree)
+	if _err != nil {
+		t.Fatalf("unexpected error from Apply(): %s", _err.Error())
+	}
+
+	_target, _err := os.Readlink(filepath.Join(_worktree, "link"))
+	if _err != nil || _target != "run.sh" {
+		t.Fatalf("unexpected link target %q: %v", _target, _err)
+	}
+	_stat, _err := os.Stat(filepath.Join(_worktree, "run.sh"))
+	if _err != nil {
+		t.Fatalf("unable to stat run.sh: %s", _err.Error())
+	} else if _stat.Mode()&0111 == 0 {
+		t.Fatalf("unexpected mode for run.sh: %v", _stat.Mode())
+	}
+	_content, _err := ioutil.ReadFile(filepath.Join(_worktree, "text"))
+	if _err != nil || string(_content) != "one\ntwo\n" {
+		t.Fatalf("unexpected content of text %q: %v", _content, _err)
+	}
+} // TestPatchModes()
+
+func TestPatchSymlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symbolic links not supported")
+	}
+
+	// untracked files must not be written through symbolic links, whether
+	// created by the patch or already present in the working copy
+	_outside, _err := ioutil.TempDir("", "")
+	if _err != nil {
+		t.Fatalf("unable to create temporary directory: %s", _err.Error())
+	}
+	defer os.RemoveAll(_outside)
+	for _, _untracked := range []map[string]interface{}{
+		{"a": _outside, "a/pwned": "pwned\n"},
+		{"a": "sub", "a/b/pwned": "pwned\n"},
+		{"existing/pwned": "pwned\n"},
+		{"existing": "pwned\n"},
+	} {
+		_dir, _err := ioutil.TempDir("", "")
+		if _err != nil {
+			t.Fatalf("unable to create temporary directory: %s", _err.Error())
+		}
+		defer os.RemoveAll(_dir)
+		_err = os.Symlink(_outside, filepath.Join(_dir, "existing"))
+		if _err != nil {
+			t.Fatalf("unable to create symbolic link: %s", _err.Error())
+		}
+
+		// craft the patch
+		_files := make(map[string][]byte)
+		_modes := make(map[string]string)
+		for _path, _content := range _untracked {
+			_files[_path] = []byte(_content.(string))
+			if _path == "a" {
+				_modes[_path] = "120000"
+			}
+		}
+		_patch, _err := gitinfo.ParsePatch(encode(t, map[string]interface{}{
+			"commit":    "0123456789abcdef0123456789abcdef01234567",
+			"untracked": _files,
+			"modes":     _modes,
+		}))
+		if _err != nil {
+			t.Fatalf("unexpected error from ParsePatch(): %s", _err.Error())
+		}
+
+		_err = _patch.Apply(_dir)
+		if _err == nil {
+			t.Fatalf("%v: expected error from Apply()", _untracked)
+		}
+		_names, _ := ioutil.ReadDir(_outside)
+		if len(_names) != 0 {
+			t.Fatalf("%v: unexpected files written outside the working "+
+				"copy: %v", _untracked, _names,
+			)
+		} else if _, _err := os.Lstat(filepath.Join(_dir, "a")); _err == nil {
+			t.Fatalf("%v: unexpected symbolic link created", _untracked)
+		}
+	}
+} // TestPatchSymlinks()
+
+//
+// helper functions
+//
+
+// encode returns the encoded patch of the given JSON value
+func encode(t *testing.T, value interface{}) string {
+	var _buffer bytes.Buffer
+	_writer := gzip.NewWriter(&_buffer)
+	_err := json.NewEncoder(_writer).Encode(value)
+	if _err == nil {
+		_err = _writer.Close()
+	}
+	if _err != nil {
+		t.Fatalf("unable to encode patch: %s", _err.Error())
+	}
+
+	_encoded := base64.StdEncoding.EncodeToString(_buffer.Bytes())
+	return "gitinfo-patch:" + strconv.Itoa(len(_encoded)) + ":" + _encoded
+} // encode()
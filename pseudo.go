@@ -0,0 +1,247 @@
+package gitinfo
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/denormal/go-gitinfo/semver"
+	"github.com/denormal/go-gittools"
+)
+
+// the timestamp format used by Go pseudo-versions
+const _PSEUDO = "20060102150405"
+
+// the number of characters of the commit hash used in pseudo-versions
+const _PSEUDO_HASH = 12
+
+var (
+	// _MODULE matches the module directive of a go.mod file
+	_MODULE = regexp.MustCompile(`(?m)^\s*module\s+"?([^"\s]+)"?`)
+
+	// _MAJOR matches the major version suffix of a module path (e.g. /v2)
+	_MAJOR = regexp.MustCompile(`/(v[0-9]+)$`)
+
+	// _PSEUDO_VERSION matches version strings that are themselves
+	// pseudo-versions, as these may never be used as a base version
+	_PSEUDO_VERSION = regexp.MustCompile(
+		`^v[0-9]+\.(0\.0-|\d+\.\d+-([^+]*\.)?0\.)\d{14}-[A-Za-z0-9]+(\+[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?$`,
+	)
+)
+
+// PseudoVersion returns the Go pseudo-version for the HEAD commit of the
+// working copy, following the rules of the Go module system. If HEAD is
+// tagged with a canonical semantic version, that version is returned, as
+// reported by "go list -m". The version is that of the module containing
+// the path used to initialise the GitInfo, so that modules in
+// subdirectories of the working copy use their own tags (e.g. sub/v1.2.3),
+// as required by the go tool. If the GitInfo instance was initialised for a
+// path not within a working copy, PseudoVersion returns the empty string.
+// An error is returned if there is a problem determining the version. If
+// HEAD is not tagged, and the working copy is a shallow clone, a
+// ShallowCloneError is returned.
+func (g *gitinfo) PseudoVersion() (string, error) {
+	// do we have a commit?
+	_commit, _err := g.Commit()
+	if _err != nil {
+		return "", _err
+	} else if _commit == nil {
+		return "", nil
+	}
+	_root := g.Root()
+
+	// determine the tag prefix and major version permitted by the module
+	//		- modules without a major version suffix accept v0 and v1 tags
+	_prefix, _major := module(_root, g.Path())
+
+	// if HEAD is tagged with an acceptable version, then that is the version
+	// the go tool will report
+	_output, _err := gittools.RunInPath(
+		_root, "tag", "--points-at", _commit.String(),
+	)
+	if _err != nil {
+		return "", _err
+	}
+	if _tag := highest(lines(_output), _prefix, _major); _tag != "" {
+		return _tag, nil
+	}
+
+	// the base version may be missing from a shallow clone
+	_err = g.complete(_commit.String())
+	if _err != nil {
+		return "", _err
+	}
+
+	// find the highest acceptable tag reachable from HEAD
+	_output, _err = gittools.RunInPath(
+		_root, "tag", "--merged", _commit.String(),
+	)
+	if _err != nil {
+		return "", _err
+	}
+	_base := highest(lines(_output), _prefix, _major)
+
+	// extract the commit time
+	_time, _err := _commit.Time()
+	if _err != nil {
+		return "", _err
+	}
+
+	return pseudoVersion(
+		_major, _base, _time, _commit.Prefix(_PSEUDO_HASH),
+	), nil
+} // PseudoVersion()
+
+// pseudoVersion returns the pseudo-version for the commit rev made at time t,
+// using older as the base version. If older is the empty string, the
+// pseudo-version is based on vMAJOR.0.0 for the given major version.
+func pseudoVersion(major, older string, t time.Time, rev string) string {
+	if major == "" {
+		major = "v0"
+	}
+	_segment := t.UTC().Format(_PSEUDO) + "-" + rev
+
+	// without a base version we have the form vX.0.0-yyyymmddhhmmss-abcdef
+	if older == "" {
+		return major + ".0.0-" + _segment
+	}
+
+	// pre-release base versions take the form vX.Y.Z-pre.0.yyyymmddhhmmss-abcdef
+	if strings.Contains(older, "-") {
+		return older + ".0." + _segment
+	}
+
+	// release base versions take the form vX.Y.(Z+1)-0.yyyymmddhhmmss-abcdef
+	_i := strings.LastIndex(older, ".") + 1
+	return older[:_i] + increment(older[_i:]) + "-0." + _segment
+} // pseudoVersion()
+
+// increment returns the decimal string representing the decimal string
+// value plus one, without imposing any limit on the size of the value.
+func increment(value string) string {
+	_digits := []byte(value)
+	for _i := len(_digits) - 1; _i >= 0; _i-- {
+		if _digits[_i] < '9' {
+			_digits[_i]++
+			return string(_digits)
+		}
+		_digits[_i] = '0'
+	}
+
+	return "1" + string(_digits)
+} // increment()
+
+// module returns the tag prefix and the major version suffix of the module
+// containing the given location, within the working copy rooted at root.
+// The module is that of the nearest go.mod file at or above location, while
+// the tag prefix is the directory of the module relative to root, omitting
+// any major version subdirectory (e.g. "sub/" for "sub/v2/go.mod" declaring
+// the module path example.com/m/sub/v2). If no go.mod file can be found,
+// module returns empty strings.
+func module(root, location string) (string, string) {
+	// start from the directory of location, if it is within the working copy
+	_rel, _err := filepath.Rel(root, location)
+	if _err != nil || _rel == ".." ||
+		strings.HasPrefix(_rel, ".."+string(filepath.Separator)) {
+		_rel = "."
+	} else if _info, _err := os.Stat(location); _err == nil && !_info.IsDir() {
+		_rel = filepath.Dir(_rel)
+	}
+
+	// find the nearest go.mod
+	for {
+		_bytes, _err := ioutil.ReadFile(filepath.Join(root, _rel, "go.mod"))
+		if _err == nil {
+			_major := major(_bytes)
+			_dir := filepath.ToSlash(_rel)
+			if _dir == "." {
+				return "", _major
+			} else if _major != "" && path.Base(_dir) == _major {
+				_dir = path.Dir(_dir)
+				if _dir == "." {
+					return "", _major
+				}
+			}
+			return _dir + "/", _major
+		} else if _rel == "." {
+			return "", ""
+		}
+		_rel = filepath.Dir(_rel)
+	}
+} // module()
+
+// major returns the major version suffix of the module path declared by the
+// given go.mod file contents, or the empty string if the module has no
+// major version suffix.
+func major(mod []byte) string {
+	// extract the module path
+	_match := _MODULE.FindSubmatch(mod)
+	if _match == nil {
+		return ""
+	}
+	_match = _MAJOR.FindSubmatch(_match[1])
+	if _match == nil || string(_match[1]) == "v0" || string(_match[1]) == "v1" {
+		return ""
+	}
+
+	return string(_match[1])
+} // major()
+
+// highest returns the highest canonical semantic version tag from the given
+// list of tags with the given prefix permitted by the major version, without
+// the prefix, or the empty string if there is no such tag.
+func highest(tags []string, prefix, major string) string {
+	var _highest semver.Version
+	for _, _tag := range tags {
+		// only tags of the module are considered
+		if !strings.HasPrefix(_tag, prefix) {
+			continue
+		}
+		_tag = strings.TrimPrefix(_tag, prefix)
+
+		// the go tool only considers canonical semantic versions
+		_version, _err := semver.Parse(_tag)
+		if _err != nil || !_version.Canonical() {
+			continue
+		} else if _PSEUDO_VERSION.MatchString(_tag) {
+			continue
+		}
+
+		// does this version match the required major version?
+		_major := "v" + strconv.FormatUint(_version.Major(), 10)
+		if major == "" {
+			if _major != "v0" && _major != "v1" {
+				continue
+			}
+		} else if _major != major {
+			continue
+		}
+
+		if _highest == nil || _version.Compare(_highest) > 0 {
+			_highest = _version
+		}
+	}
+
+	if _highest == nil {
+		return ""
+	}
+	return _highest.String()
+} // highest()
+
+// lines returns the non-empty, trimmed lines of the given output
+func lines(output []byte) []string {
+	_lines := make([]string, 0)
+	for _, _line := range strings.Split(string(output), "\n") {
+		_line = strings.TrimSpace(_line)
+		if _line != "" {
+			_lines = append(_lines, _line)
+		}
+	}
+
+	return _lines
+} // lines()
@@ -0,0 +1,258 @@
+package gitinfo_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/denormal/go-gitinfo"
+	"github.com/denormal/go-gittools"
+)
+
+// the commit time for the HEAD of the pseudo-version test repositories,
+// deliberately not UTC to ensure the time is converted
+const _WHEN = "2024-01-02T17:04:05+02:00"
+
+type pseudo struct {
+	module   string   // the module path for go.mod (if any)
+	base     []string // the tags for the base commit
+	head     []string // the tags for the HEAD commit
+	expected string   // the expected version (%s is the HEAD hash prefix)
+}
+
+func TestPseudoVersion(t *testing.T) {
+	// if we don't have git installed, then skip this test
+	if !gittools.HasGit() {
+		t.Skip("git not installed")
+	}
+
+	// these tests follow the semantics of golang.org/x/mod/module
+	_tests := []pseudo{
+		{"", nil, nil, "v0.0.0-20240102150405-%s"},
+		{"", []string{"v0.0.0"}, nil, "v0.0.1-0.20240102150405-%s"},
+		{"", []string{"v1.2.3"}, nil, "v1.2.4-0.20240102150405-%s"},
+		{"", []string{"v1.2.99999999999999999"}, nil,
+			"v1.2.100000000000000000-0.20240102150405-%s"},
+		{"", []string{"v1.0.0-pre"}, nil, "v1.0.0-pre.0.20240102150405-%s"},
+		{"", []string{"v1.2.99999999999999999-pre"}, nil,
+			"v1.2.99999999999999999-pre.0.20240102150405-%s"},
+		{"", []string{"v1.2.3", "v1.10.0", "v1.9.0"}, nil,
+			"v1.10.1-0.20240102150405-%s"},
+		{"", []string{"v1.0.0-rc.2", "v1.0.0-rc.10"}, nil,
+			"v1.0.0-rc.10.0.20240102150405-%s"},
+		{"", []string{"v1.0.0-alpha.1", "v1.0.0-beta"}, nil,
+			"v1.0.0-beta.0.20240102150405-%s"},
+		{"", []string{"v1.0.0-rc.1", "v1.0.0"}, nil,
+			"v1.0.1-0.20240102150405-%s"},
+		//		- non-canonical versions are ignored
+		{"", []string{"v1.2.3", "v1.3", "v1.4.0+meta", "1.5.0", "v1.06.0"},
+			nil, "v1.2.4-0.20240102150405-%s"},
+		//		- pseudo-versions are ignored
+		{"", []string{"v0.0.0-20200101000000-abcdefabcdef"}, nil,
+			"v0.0.0-20240102150405-%s"},
+		//		- major versions must match the module path
+		{"", []string{"v1.2.3", "v2.0.0"}, nil, "v1.2.4-0.20240102150405-%s"},
+		{"example.com/m/v2", []string{"v1.2.3"}, nil,
+			"v2.0.0-20240102150405-%s"},
+		{"example.com/m/v2", []string{"v1.5.0", "v2.1.0"}, nil,
+			"v2.1.1-0.20240102150405-%s"},
+		//		- tagged commits report their tag
+		{"", []string{"v1.0.0"}, []string{"v1.0.1"}, "v1.0.1"},
+		{"", []string{"v1.0.0"}, []string{"v1.1"}, "v1.0.1-0.20240102150405-%s"},
+		{"example.com/m/v2", nil, []string{"v1.0.1", "v2.0.0-rc.1"},
+			"v2.0.0-rc.1"},
+	}
+
+	for _, _test := range _tests {
+		_dir := repository(t)
+		defer os.RemoveAll(_dir)
+
+		// create the base commit
+		if _test.module != "" {
+			_err := ioutil.WriteFile(
+				filepath.Join(_dir, "go.mod"),
+				[]byte("module "+_test.module+"\n"),
+				0644,
+			)
+			if _err != nil {
+				t.Fatalf("unable to write go.mod: %s", _err.Error())
+			}
+		}
+		commit(t, _dir, "2023-06-01T00:00:00Z", "base", _test.base...)
+
+		// create the HEAD commit
+		_hash := commit(t, _dir, _WHEN, "head", _test.head...)
+		_expected := _test.expected
+		if strings.Contains(_expected, "%s") {
+			_expected = fmt.Sprintf(_expected, _hash[:12])
+		}
+
+		// ensure the pseudo-version is as expected
+		_info, _err := gitinfo.NewWithPath(_dir)
+		if _err != nil {
+			t.Fatalf("unexpected error from NewWithPath(): %s", _err.Error())
+		}
+		_version, _err := _info.PseudoVersion()
+		if _err != nil {
+			t.Fatalf("unexpected error from PseudoVersion(): %s", _err.Error())
+		} else if _version != _expected {
+			t.Fatalf(
+				"%v: unexpected pseudo-version; expected %q, got %q",
+				_test, _expected, _version,
+			)
+		}
+	}
+
+	// ensure paths outside a working copy report no pseudo-version
+	_dir, _err := ioutil.TempDir("", "")
+	if _err != nil {
+		t.Fatalf("unable to create temporary directory: %s", _err.Error())
+	}
+	defer os.RemoveAll(_dir)
+
+	_info, _err := gitinfo.NewWithPath(_dir)
+	if _err != nil {
+		t.Fatalf("%q: unexpected error from New(): %s", _dir, _err.Error())
+	}
+	_version, _err := _info.PseudoVersion()
+	if _err != nil {
+		t.Fatalf("unexpected error from PseudoVersion(): %s", _err.Error())
+	} else if _version != "" {
+		t.Fatalf(
+			"unexpected pseudo-version; expected %q, got %q", "", _version,
+		)
+	}
+} // TestPseudoVersion()
+
+func TestPseudoVersionModules(t *testing.T) {
+	// if we don't have git installed, then skip this test
+	if !gittools.HasGit() {
+		t.Skip("git not installed")
+	}
+
+	// modules in subdirectories use tags prefixed with their directory,
+	// without any major version subdirectory, as with "go list -m"
+	_tests := []struct {
+		dir string // the directory of go.mod, relative to the root
+		pseudo
+	}{
+		{"sub", pseudo{"example.com/m/sub",
+			[]string{"v1.5.0", "sub/v1.2.3", "other/v1.9.0"}, nil,
+			"v1.2.4-0.20240102150405-%s"}},
+		{"sub", pseudo{"example.com/m/sub", []string{"v1.5.0"}, nil,
+			"v0.0.0-20240102150405-%s"}},
+		{"sub", pseudo{"example.com/m/sub/v2",
+			[]string{"sub/v1.0.0", "sub/v2.1.0", "v2.5.0"}, nil,
+			"v2.1.1-0.20240102150405-%s"}},
+		{"sub/v2", pseudo{"example.com/m/sub/v2",
+			[]string{"sub/v2.0.0", "sub/v2/v2.3.0"}, nil,
+			"v2.0.1-0.20240102150405-%s"}},
+		{"v3", pseudo{"example.com/m/v3", []string{"v3.1.0"}, nil,
+			"v3.1.1-0.20240102150405-%s"}},
+		{"sub", pseudo{"example.com/m/sub", []string{"sub/v1.0.0"},
+			[]string{"v1.1.0", "sub/v1.0.1"}, "v1.0.1"}},
+	}
+
+	for _, _test := range _tests {
+		_dir := repository(t)
+		defer os.RemoveAll(_dir)
+
+		// create the module, and a package within it
+		_pkg := filepath.Join(_dir, filepath.FromSlash(_test.dir), "pkg")
+		_err := os.MkdirAll(_pkg, 0755)
+		if _err != nil {
+			t.Fatalf("unable to create %s: %s", _pkg, _err.Error())
+		}
+		_err = ioutil.WriteFile(
+			filepath.Join(_dir, filepath.FromSlash(_test.dir), "go.mod"),
+			[]byte("module "+_test.module+"\n"),
+			0644,
+		)
+		if _err != nil {
+			t.Fatalf("unable to write go.mod: %s", _err.Error())
+		}
+		_err = ioutil.WriteFile(
+			filepath.Join(_pkg, "pkg.go"), []byte("package pkg\n"), 0644,
+		)
+		if _err != nil {
+			t.Fatalf("unable to write pkg.go: %s", _err.Error())
+		}
+		commit(t, _dir, "2023-06-01T00:00:00Z", "base", _test.base...)
+		_hash := commit(t, _dir, _WHEN, "head", _test.head...)
+		_expected := _test.expected
+		if strings.Contains(_expected, "%s") {
+			_expected = fmt.Sprintf(_expected, _hash[:12])
+		}
+
+		// ensure the pseudo-version is that of the module of the package
+		_info, _err := gitinfo.NewWithPath(_pkg)
+		if _err != nil {
+			t.Fatalf("unexpected error from NewWithPath(): %s", _err.Error())
+		}
+		_version, _err := _info.PseudoVersion()
+		if _err != nil {
+			t.Fatalf("unexpected error from PseudoVersion(): %s", _err.Error())
+		} else if _version != _expected {
+			t.Fatalf(
+				"%s %v: unexpected pseudo-version; expected %q, got %q",
+				_test.dir, _test.pseudo, _expected, _version,
+			)
+		}
+	}
+} // TestPseudoVersionModules()
+
+//
+// helper functions
+//
+
+// repository creates a new, empty git repository in a temporary directory
+func repository(t *testing.T) string {
+	_dir, _err := ioutil.TempDir("", "")
+	if _err != nil {
+		t.Fatalf("unable to create temporary directory: %s", _err.Error())
+	}
+
+	// resolve any symbolic links so paths match those reported by git
+	_dir, _err = filepath.EvalSymlinks(_dir)
+	if _err != nil {
+		t.Fatalf("unable to resolve temporary directory: %s", _err.Error())
+	}
+
+	git(t, _dir, "init", "-q")
+	return _dir
+} // repository()
+
+// commit commits all changes in the repository at the given time, tags
+// the commit with the given tags, and returns the commit hash
+func commit(t *testing.T, dir, when, msg string, tags ...string) string {
+	_reset, _err := env("GIT_COMMITTER_DATE", when)
+	if _err != nil {
+		t.Fatalf("unable to set GIT_COMMITTER_DATE: %s", _err.Error())
+	}
+	defer _reset()
+
+	git(t, dir, "add", "-A")
+	git(t, dir,
+		"-c", "user.name=gitinfo", "-c", "user.email=gitinfo@example.com",
+		"-c", "commit.gpgsign=false",
+		"commit", "-q", "--allow-empty", "--date", when, "-m", msg,
+	)
+	for _, _tag := range tags {
+		git(t, dir, "tag", _tag)
+	}
+
+	return strings.TrimSpace(git(t, dir, "rev-parse", "HEAD"))
+} // commit()
+
+// git runs the git command in the given directory, returning its output
+func git(t *testing.T, dir string, args ...string) string {
+	_output, _err := gittools.RunInPath(dir, args...)
+	if _err != nil {
+		t.Fatalf("git %s: %s", strings.Join(args, " "), _err.Error())
+	}
+
+	return string(_output)
+} // git()
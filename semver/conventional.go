@@ -0,0 +1,203 @@
+package semver
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Change represents the significance of a change to a code base, in terms
+// of the version increment it requires.
+type Change int
+
+const (
+	NONE  Change = iota // no version increment required
+	PATCH               // backwards-compatible bug fixes
+	MINOR               // backwards-compatible features
+	MAJOR               // incompatible changes
+)
+
+// the footer tokens identifying breaking changes
+const (
+	BREAKING_CHANGE        = "BREAKING CHANGE"
+	BREAKING_CHANGE_HYPHEN = "BREAKING-CHANGE"
+)
+
+var (
+	// _HEADER matches the header of a Conventional Commits message, of the
+	// form type(scope)!: description
+	_HEADER = regexp.MustCompile(`^([A-Za-z]+)(\(([^()]*)\))?(!)?: (.*)$`)
+
+	// _FOOTER matches the start of a footer, of the form "token: value" or
+	// "token #value"
+	_FOOTER = regexp.MustCompile(`^(BREAKING[ -]CHANGE|[A-Za-z0-9-]+)(: | #)(.*)$`)
+)
+
+// Message represents a commit message, parsed according to
+// https://www.conventionalcommits.org.
+type Message interface {
+	// Conventional returns true if the message follows Conventional Commits.
+	Conventional() bool
+
+	// Type returns the lower-case type of the commit (e.g. "feat" or "fix"),
+	// or the empty string if the message is not conventional.
+	Type() string
+
+	// Scope returns the scope of the commit, or the empty string if the
+	// commit has no scope.
+	Scope() string
+
+	// Description returns the description from the message header. If the
+	// message is not conventional, Description returns the entire header.
+	Description() string
+
+	// Body returns the body of the message, excluding the header and
+	// footers.
+	Body() string
+
+	// Footers returns the footers of the message, keyed by their token, with
+	// the values given in message order.
+	Footers() map[string][]string
+
+	// Breaking returns true if the message describes a breaking change.
+	Breaking() bool
+
+	// Notes returns the descriptions of the breaking changes in the message,
+	// taken from the "BREAKING CHANGE" footers, or the description if the
+	// breaking change is only indicated by a "!" in the header.
+	Notes() []string
+
+	// Change returns the significance of the change described by the
+	// message.
+	Change() Change
+}
+
+type message struct {
+	conventional bool
+	typ          string
+	scope        string
+	description  string
+	body         string
+	footers      map[string][]string
+	breaking     bool
+	notes        []string
+}
+
+// ParseMessage returns the Message for the given commit message. Messages
+// that do not follow Conventional Commits are returned with an empty type.
+func ParseMessage(msg string) Message {
+	_lines := strings.Split(strings.TrimSpace(msg), "\n")
+	_m := &message{
+		description: strings.TrimSpace(_lines[0]),
+		footers:     make(map[string][]string),
+	}
+
+	// is this a conventional commit?
+	_match := _HEADER.FindStringSubmatch(_m.description)
+	if _match != nil {
+		_m.conventional = true
+		_m.typ = strings.ToLower(_match[1])
+		_m.scope = strings.TrimSpace(_match[3])
+		_m.breaking = _match[4] == "!"
+		_m.description = strings.TrimSpace(_match[5])
+	}
+
+	// find the start of the footers
+	//		- footers follow a blank line
+	_start := len(_lines)
+	for _i := 2; _i < len(_lines); _i++ {
+		if strings.TrimSpace(_lines[_i-1]) == "" && _FOOTER.MatchString(_lines[_i]) {
+			_start = _i
+			break
+		}
+	}
+	if len(_lines) > 1 {
+		_m.body = strings.TrimSpace(strings.Join(_lines[1:_start], "\n"))
+	}
+
+	// extract the footers
+	//		- footer values continue until the next footer token
+	_token, _value := "", []string{}
+	_add := func() {
+		if _token != "" {
+			_v := strings.TrimSpace(strings.Join(_value, "\n"))
+			_m.footers[_token] = append(_m.footers[_token], _v)
+			if _token == BREAKING_CHANGE || _token == BREAKING_CHANGE_HYPHEN {
+				_m.breaking = true
+				_m.notes = append(_m.notes, _v)
+			}
+		}
+	}
+	for _, _line := range _lines[_start:] {
+		_match := _FOOTER.FindStringSubmatch(_line)
+		if _match != nil {
+			_add()
+			_token, _value = _match[1], []string{_match[3]}
+		} else {
+			_value = append(_value, _line)
+		}
+	}
+	_add()
+
+	// a breaking change without notes is described by its header
+	if _m.breaking && len(_m.notes) == 0 {
+		_m.notes = []string{_m.description}
+	}
+
+	return _m
+} // ParseMessage()
+
+func (m *message) Conventional() bool           { return m.conventional }
+func (m *message) Type() string                 { return m.typ }
+func (m *message) Scope() string                { return m.scope }
+func (m *message) Description() string          { return m.description }
+func (m *message) Body() string                 { return m.body }
+func (m *message) Footers() map[string][]string { return m.footers }
+func (m *message) Breaking() bool               { return m.breaking }
+func (m *message) Notes() []string              { return m.notes }
+
+// Change returns the significance of the change described by the message.
+// Breaking changes are MAJOR, "feat" commits are MINOR and "fix" commits are
+// PATCH changes. All other messages, including those that do not follow
+// Conventional Commits, are NONE.
+func (m *message) Change() Change {
+	if !m.conventional {
+		return NONE
+	} else if m.breaking {
+		return MAJOR
+	}
+
+	switch m.typ {
+	case "feat":
+		return MINOR
+	case "fix":
+		return PATCH
+	default:
+		return NONE
+	}
+} // Change()
+
+// String returns the name of the change.
+func (c Change) String() string {
+	switch c {
+	case PATCH:
+		return "patch"
+	case MINOR:
+		return "minor"
+	case MAJOR:
+		return "major"
+	default:
+		return "none"
+	}
+} // String()
+
+// Classify returns the Change for the given commit message, according to
+// https://www.conventionalcommits.org. Messages with a "!" after the type or
+// scope, or a "BREAKING CHANGE" footer are MAJOR changes, "feat" commits are
+// MINOR changes and "fix" commits are PATCH changes. All other messages,
+// including those that do not follow Conventional Commits, are NONE.
+func Classify(msg string) Change {
+	return ParseMessage(msg).Change()
+} // Classify()
+
+// ensure message implements the Message interface
+var _ Message = &message{}
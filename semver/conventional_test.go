@@ -0,0 +1,127 @@
+package semver_test
+
+import (
+	"testing"
+
+	"github.com/denormal/go-gitinfo/semver"
+)
+
+func TestClassify(t *testing.T) {
+	for _, _test := range []struct {
+		message  string
+		expected semver.Change
+	}{
+		{"", semver.NONE},
+		{"update the README", semver.NONE},
+		{"docs: update the README", semver.NONE},
+		{"chore(deps): bump go-gittools", semver.NONE},
+		{"fix: handle empty repositories", semver.PATCH},
+		{"fix(cli): handle empty repositories\n\nCloses #12", semver.PATCH},
+		{"feat: add next-version", semver.MINOR},
+		{"Feat(semver): add next-version", semver.MINOR},
+		{"feat!: remove Version()", semver.MAJOR},
+		{"refactor(api)!: rename Commit()", semver.MAJOR},
+		{
+			"feat: add Next()\n\nBREAKING CHANGE: Latest() now returns nil",
+			semver.MAJOR,
+		},
+		{"fix: typo\n\nBREAKING-CHANGE: none really", semver.MAJOR},
+		//		- the breaking change footer must start the line
+		{"fix: typo\n\nthis is not a BREAKING CHANGE: honest", semver.PATCH},
+		//		- the description must follow ": "
+		{"feat:missing space", semver.NONE},
+	} {
+		_change := semver.Classify(_test.message)
+		if _change != _test.expected {
+			t.Fatalf(
+				"%q: unexpected change; expected %s, got %s",
+				_test.message, _test.expected, _change,
+			)
+		}
+	}
+} // TestClassify()
+
+func TestParseMessage(t *testing.T) {
+	// parse a conventional message with a body and footers
+	_message := semver.ParseMessage(
+		"feat(API)!: drop Version()\n" +
+			"\n" +
+			"Version() has been deprecated for some time.\n" +
+			"\n" +
+			"Use Git() instead.\n" +
+			"\n" +
+			"BREAKING CHANGE: Version() has been removed;\n" +
+			"  callers should use Git()\n" +
+			"Refs #42\n" +
+			"Reviewed-by: Jane Doe <jane@example.com>\n",
+	)
+	if !_message.Conventional() {
+		t.Fatal("expected conventional message")
+	} else if _message.Type() != "feat" {
+		t.Fatalf("unexpected type; expected %q, got %q", "feat", _message.Type())
+	} else if _message.Scope() != "API" {
+		t.Fatalf("unexpected scope; expected %q, got %q", "API", _message.Scope())
+	} else if _message.Description() != "drop Version()" {
+		t.Fatalf(
+			"unexpected description; expected %q, got %q",
+			"drop Version()", _message.Description(),
+		)
+	} else if !_message.Breaking() {
+		t.Fatal("expected breaking change")
+	} else if _message.Change() != semver.MAJOR {
+		t.Fatalf(
+			"unexpected change; expected %s, got %s",
+			semver.MAJOR, _message.Change(),
+		)
+	}
+
+	_body := "Version() has been deprecated for some time.\n\nUse Git() instead."
+	if _message.Body() != _body {
+		t.Fatalf("unexpected body; expected %q, got %q", _body, _message.Body())
+	}
+
+	_notes := _message.Notes()
+	_note := "Version() has been removed;\n  callers should use Git()"
+	if len(_notes) != 1 || _notes[0] != _note {
+		t.Fatalf("unexpected notes; expected [%q], got %q", _note, _notes)
+	}
+
+	_footers := _message.Footers()
+	for _token, _value := range map[string]string{
+		semver.BREAKING_CHANGE: _note,
+		"Refs":                 "42",
+		"Reviewed-by":          "Jane Doe <jane@example.com>",
+	} {
+		if len(_footers[_token]) != 1 || _footers[_token][0] != _value {
+			t.Fatalf(
+				"unexpected %q footer; expected [%q], got %q",
+				_token, _value, _footers[_token],
+			)
+		}
+	}
+
+	// a "!" without a breaking change footer uses the description as the note
+	_message = semver.ParseMessage("fix!: reject empty paths")
+	_notes = _message.Notes()
+	if len(_notes) != 1 || _notes[0] != "reject empty paths" {
+		t.Fatalf(
+			"unexpected notes; expected [%q], got %q",
+			"reject empty paths", _notes,
+		)
+	}
+
+	// messages that are not conventional retain their header
+	_message = semver.ParseMessage("Merge branch 'master'\n\nSigned-off-by: me")
+	if _message.Conventional() {
+		t.Fatal("unexpected conventional message")
+	} else if _message.Type() != "" {
+		t.Fatalf("unexpected type; expected %q, got %q", "", _message.Type())
+	} else if _message.Description() != "Merge branch 'master'" {
+		t.Fatalf(
+			"unexpected description; expected %q, got %q",
+			"Merge branch 'master'", _message.Description(),
+		)
+	} else if len(_message.Footers()["Signed-off-by"]) != 1 {
+		t.Fatalf("unexpected footers: %v", _message.Footers())
+	}
+} // TestParseMessage()
@@ -0,0 +1,7 @@
+/*
+Package semver provides parsing and comparison of semantic versions, as
+defined by https://semver.org, and suggests the next version of a git
+working copy by classifying the commits made since its most recent version
+tag according to https://www.conventionalcommits.org.
+*/
+package semver
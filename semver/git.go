@@ -0,0 +1,143 @@
+package semver
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/denormal/go-gittools"
+)
+
+// Tags returns the semantic version tags reachable from the HEAD commit of
+// the working copy containing path, in ascending order of precedence. Tags
+// that are not valid semantic versions are ignored. If path is "", Tags
+// examines the current process working directory.
+func Tags(path string) ([]Version, error) {
+	_root, _err := root(path)
+	if _err != nil {
+		return nil, _err
+	}
+
+	// extract the tags reachable from HEAD
+	_output, _err := gittools.RunInPath(_root, "tag", "--merged", "HEAD")
+	if _err != nil {
+		return nil, _err
+	}
+
+	// parse the tags as versions
+	_versions := make([]Version, 0)
+	for _, _tag := range strings.Split(string(_output), "\n") {
+		_version, _err := Parse(strings.TrimSpace(_tag))
+		if _err == nil {
+			_versions = append(_versions, _version)
+		}
+	}
+	sort.SliceStable(_versions, func(i, j int) bool {
+		return _versions[i].Compare(_versions[j]) < 0
+	})
+
+	return _versions, nil
+} // Tags()
+
+// Latest returns the highest semantic version tag reachable from the HEAD
+// commit of the working copy containing path, or nil if there is no such
+// tag. If path is "", Latest examines the current process working directory.
+func Latest(path string) (Version, error) {
+	_versions, _err := Tags(path)
+	if _err != nil {
+		return nil, _err
+	} else if len(_versions) == 0 {
+		return nil, nil
+	}
+
+	return _versions[len(_versions)-1], nil
+} // Latest()
+
+// Since returns the most significant Change of the commits reachable from
+// the HEAD commit of the working copy containing path that are not reachable
+// from the given version tag. If version is nil, all commits reachable from
+// HEAD are examined. If path is "", Since examines the current process
+// working directory. If the working copy is a shallow clone missing some of
+// the examined commits, Since returns a ShallowCloneError.
+func Since(path string, version Version) (Change, error) {
+	_root, _err := root(path)
+	if _err != nil {
+		return NONE, _err
+	}
+
+	// extract the messages of the commits since the version tag
+	_range := "HEAD"
+	if version != nil {
+		_range = "refs/tags/" + version.String() + ".." + _range
+	}
+	//		- shallow clones may be missing commits, or version tags
+	_err = complete(_root, _range)
+	if _err != nil {
+		return NONE, _err
+	}
+	_output, _err := gittools.RunInPath(
+		_root, "log", "--format=%B%x00", _range,
+	)
+	if _err != nil {
+		return NONE, _err
+	}
+
+	// find the most significant change
+	_change := NONE
+	for _, _message := range strings.Split(string(_output), "\x00") {
+		if _c := Classify(_message); _c > _change {
+			_change = _c
+		}
+	}
+
+	return _change, nil
+} // Since()
+
+// Next returns the suggested next version for the working copy containing
+// path, determined from the highest semantic version tag reachable from HEAD
+// and the commits made since that tag. If there are no version tags, the
+// next version is determined from v0.0.0. If there are no changes requiring
+// a new version, Next returns the current version. If path is "", Next
+// examines the current process working directory. If the working copy is a
+// shallow clone missing the commits since the latest version tag, Next
+// returns a ShallowCloneError.
+func Next(path string) (Version, error) {
+	_latest, _err := Latest(path)
+	if _err != nil {
+		return nil, _err
+	}
+
+	// determine the most significant change since the latest version
+	_change, _err := Since(path, _latest)
+	if _err != nil {
+		return nil, _err
+	} else if _latest == nil {
+		_latest = New(0, 0, 0)
+	}
+
+	return _latest.Next(_change), nil
+} // Next()
+
+// root returns the root of the working copy containing path, or an error if
+// path is not within a working copy.
+func root(path string) (string, error) {
+	var _err error
+
+	// if we have an empty path, then choose the current working directory
+	if path == "" {
+		path, _err = os.Getwd()
+		if _err != nil {
+			return "", _err
+		}
+	}
+
+	// ensure we are in a git working copy
+	_root, _err := gittools.WorkingCopy(path)
+	if _err != nil {
+		return "", _err
+	} else if _root == "" {
+		return "", gittools.MissingWorkingCopyError
+	}
+
+	return _root, nil
+} // root()
@@ -0,0 +1,166 @@
+package semver_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/denormal/go-gitinfo/semver"
+	"github.com/denormal/go-gittools"
+)
+
+func TestGit(t *testing.T) {
+	// if we don't have git installed, then skip this test
+	if !gittools.HasGit() {
+		t.Skip("git not installed")
+	}
+
+	_dir, _err := ioutil.TempDir("", "")
+	if _err != nil {
+		t.Fatalf("unable to create temporary directory: %s", _err.Error())
+	}
+	defer os.RemoveAll(_dir)
+	_dir, _ = filepath.EvalSymlinks(_dir)
+	git(t, _dir, "init", "-q")
+
+	// a repository without tags starts from v0.0.0
+	commit(t, _dir, "chore: initial commit")
+	next(t, _dir, "v0.0.0")
+	commit(t, _dir, "fix: first fix")
+	next(t, _dir, "v0.0.1")
+	commit(t, _dir, "feat: first feature")
+	next(t, _dir, "v0.1.0")
+
+	// once tagged, changes are determined from the latest tag
+	git(t, _dir, "tag", "v0.1.0")
+	git(t, _dir, "tag", "not-a-version")
+	next(t, _dir, "v0.1.0")
+	commit(t, _dir, "docs: describe the release process")
+	next(t, _dir, "v0.1.0")
+	commit(t, _dir, "fix(semver): compare pre-releases")
+	next(t, _dir, "v0.1.1")
+	commit(t, _dir, "feat(semver)!: rename Latest()")
+	next(t, _dir, "v1.0.0")
+
+	// the highest version is used, regardless of tag order
+	git(t, _dir, "tag", "v1.0.0-rc.1")
+	git(t, _dir, "tag", "v0.9.0")
+	next(t, _dir, "v1.0.0-rc.1")
+	commit(t, _dir, "fix: last minute fix")
+	next(t, _dir, "v1.0.0")
+
+	// ensure Latest() and Tags() report all versions
+	_latest, _err := semver.Latest(_dir)
+	if _err != nil {
+		t.Fatalf("unexpected error from Latest(): %s", _err.Error())
+	} else if _latest.String() != "v1.0.0-rc.1" {
+		t.Fatalf(
+			"unexpected latest version; expected %q, got %q",
+			"v1.0.0-rc.1", _latest.String(),
+		)
+	}
+	_tags, _err := semver.Tags(_dir)
+	if _err != nil {
+		t.Fatalf("unexpected error from Tags(): %s", _err.Error())
+	}
+	_strings := make([]string, 0, len(_tags))
+	for _, _tag := range _tags {
+		_strings = append(_strings, _tag.String())
+	}
+	_expected := "v0.1.0 v0.9.0 v1.0.0-rc.1"
+	if strings.Join(_strings, " ") != _expected {
+		t.Fatalf(
+			"unexpected tags; expected %q, got %q",
+			_expected, strings.Join(_strings, " "),
+		)
+	}
+
+	// ensure paths outside a working copy report an error
+	_tmp, _err := ioutil.TempDir("", "")
+	if _err != nil {
+		t.Fatalf("unable to create temporary directory: %s", _err.Error())
+	}
+	defer os.RemoveAll(_tmp)
+
+	_next, _err := semver.Next(_tmp)
+	if _err == nil {
+		t.Fatalf("expected error from Next(); got %v", _next)
+	}
+} // TestGit()
+
+func TestShallow(t *testing.T) {
+	// if we don't have git installed, then skip this test
+	if !gittools.HasGit() {
+		t.Skip("git not installed")
+	}
+
+	_dir, _err := ioutil.TempDir("", "")
+	if _err != nil {
+		t.Fatalf("unable to create temporary directory: %s", _err.Error())
+	}
+	defer os.RemoveAll(_dir)
+	_dir, _ = filepath.EvalSymlinks(_dir)
+	_origin := filepath.Join(_dir, "origin")
+	_clone := filepath.Join(_dir, "clone")
+	git(t, _dir, "init", "-q", _origin)
+
+	commit(t, _origin, "feat: first feature")
+	git(t, _origin, "tag", "v0.1.0")
+	commit(t, _origin, "feat!: breaking change")
+	commit(t, _origin, "fix: first fix")
+
+	// a shallow clone is missing the commits since the version tag
+	git(t, _dir,
+		"clone", "-q", "--depth=1", "file://"+filepath.ToSlash(_origin),
+		_clone,
+	)
+	_next, _err := semver.Next(_clone)
+	if _, _ok := _err.(*semver.ShallowCloneError); !_ok {
+		t.Fatalf("expected ShallowCloneError from Next(); got %v, %v",
+			_next, _err,
+		)
+	}
+	_, _err = semver.Since(_clone, nil)
+	if _, _ok := _err.(*semver.ShallowCloneError); !_ok {
+		t.Fatalf("expected ShallowCloneError from Since(); got %v", _err)
+	}
+
+	// once the history is complete, the next version is known
+	git(t, _clone, "fetch", "-q", "--unshallow", "--tags")
+	next(t, _clone, "v1.0.0")
+} // TestShallow()
+
+//
+// helper functions
+//
+
+func next(t *testing.T, dir, expected string) {
+	_next, _err := semver.Next(dir)
+	if _err != nil {
+		t.Fatalf("unexpected error from Next(): %s", _err.Error())
+	} else if _next.String() != expected {
+		t.Fatalf(
+			"unexpected next version; expected %q, got %q",
+			expected, _next.String(),
+		)
+	}
+} // next()
+
+func commit(t *testing.T, dir, msg string) {
+	git(t, dir,
+		"-c", "user.name=gitinfo", "-c", "user.email=gitinfo@example.com",
+		"-c", "commit.gpgsign=false",
+		"commit", "-q", "--allow-empty", "-m", msg,
+	)
+} // commit()
+
+func git(t *testing.T, dir string, args ...string) string {
+	_output, _err := gittools.RunInPath(dir, args...)
+	if _err != nil {
+		t.Fatalf("git %s: %s", strings.Join(args, " "), _err.Error())
+	}
+
+	return string(_output)
+} // git()
@@ -0,0 +1,71 @@
+package semver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/denormal/go-gittools"
+)
+
+// ShallowCloneError is the error returned when the commits required to
+// suggest the next version are missing, because the working copy is a
+// shallow clone.
+type ShallowCloneError struct {
+	// Boundary lists the shallow boundary commits of the clone, whose
+	// parents are missing from the clone.
+	Boundary []string
+}
+
+// Error returns the error message, describing the shallow clone.
+func (e *ShallowCloneError) Error() string {
+	return "history unavailable for shallow clone; fetch with --unshallow"
+} // Error()
+
+// complete returns a ShallowCloneError if the history of the commits given
+// by the "git rev-list" arguments is truncated by the shallow boundary of
+// the working copy rooted at root, or nil if the history is complete
+func complete(root string, args ...string) error {
+	// locate the shallow file
+	//		- relative paths are relative to the working copy root
+	_output, _err := gittools.RunInPath(
+		root, "rev-parse", "--git-path", "shallow",
+	)
+	if _err != nil {
+		return _err
+	}
+	_path := strings.TrimSpace(string(_output))
+	if !filepath.IsAbs(_path) {
+		_path = filepath.Join(root, _path)
+	}
+	_bytes, _err := ioutil.ReadFile(_path)
+	if os.IsNotExist(_err) {
+		return nil
+	} else if _err != nil {
+		return _err
+	}
+	_boundary := strings.Fields(string(_bytes))
+	if len(_boundary) == 0 {
+		return nil
+	}
+
+	// list the commits, and look for any boundary commits
+	_output, _err = gittools.RunInPath(
+		root, append([]string{"rev-list"}, args...)...,
+	)
+	if _err != nil {
+		return _err
+	}
+	_set := make(map[string]bool, len(_boundary))
+	for _, _hash := range _boundary {
+		_set[_hash] = true
+	}
+	for _, _hash := range strings.Fields(string(_output)) {
+		if _set[_hash] {
+			return &ShallowCloneError{Boundary: _boundary}
+		}
+	}
+
+	return nil
+} // complete()
@@ -0,0 +1,293 @@
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version represents a semantic version.
+type Version interface {
+	// Major returns the major version number.
+	Major() uint64
+
+	// Minor returns the minor version number.
+	Minor() uint64
+
+	// Patch returns the patch version number.
+	Patch() uint64
+
+	// Prerelease returns the dot-separated pre-release identifiers of the
+	// version, or the empty string if this is not a pre-release version.
+	Prerelease() string
+
+	// Build returns the dot-separated build metadata of the version, or the
+	// empty string if the version has no build metadata.
+	Build() string
+
+	// Prefix returns the prefix of the version string, which is either "v"
+	// or the empty string.
+	Prefix() string
+
+	// Canonical returns true if this version is of the canonical form
+	// vMAJOR.MINOR.PATCH[-PRERELEASE] used by the Go module system.
+	Canonical() bool
+
+	// Compare returns -1, 0 or 1 if this version has lower, equal or higher
+	// precedence than the given version. Build metadata and the version
+	// prefix are ignored.
+	Compare(v Version) int
+
+	// Next returns the version following this version for the given change.
+	// If this is a pre-release version, Next returns the release of this
+	// version unless the change requires a higher version. Build metadata
+	// is discarded, while the version prefix is retained. If the change is
+	// NONE, Next returns this version.
+	Next(c Change) Version
+
+	// String returns the version string.
+	String() string
+}
+
+type version struct {
+	prefix     string
+	major      uint64
+	minor      uint64
+	patch      uint64
+	prerelease string
+	build      string
+}
+
+// New returns the Version for the given major, minor and patch numbers,
+// with the conventional "v" prefix.
+func New(major, minor, patch uint64) Version {
+	return &version{prefix: "v", major: major, minor: minor, patch: patch}
+} // New()
+
+// Parse returns the Version represented by the given string, or an error if
+// the string is not a valid semantic version. The version string may have
+// an optional "v" prefix.
+func Parse(s string) (Version, error) {
+	_v := &version{}
+	_s := s
+	if strings.HasPrefix(_s, "v") {
+		_v.prefix, _s = "v", _s[1:]
+	}
+
+	// extract the build metadata and pre-release identifiers
+	if _i := strings.Index(_s, "+"); _i >= 0 {
+		_s, _v.build = _s[:_i], _s[_i+1:]
+		if !identifiers(_v.build, false) {
+			return nil, fmt.Errorf("invalid build metadata in %q", s)
+		}
+	}
+	if _i := strings.Index(_s, "-"); _i >= 0 {
+		_s, _v.prerelease = _s[:_i], _s[_i+1:]
+		if !identifiers(_v.prerelease, true) {
+			return nil, fmt.Errorf("invalid pre-release in %q", s)
+		}
+	}
+
+	// the version core must be three numeric identifiers
+	_parts := strings.Split(_s, ".")
+	if len(_parts) != 3 {
+		return nil, fmt.Errorf("invalid version %q", s)
+	}
+	_numbers := make([]uint64, len(_parts))
+	for _i, _part := range _parts {
+		if !numeric(_part) {
+			return nil, fmt.Errorf("invalid version %q", s)
+		}
+		_n, _err := strconv.ParseUint(_part, 10, 64)
+		if _err != nil {
+			return nil, fmt.Errorf("invalid version %q: %s", s, _err.Error())
+		}
+		_numbers[_i] = _n
+	}
+	_v.major, _v.minor, _v.patch = _numbers[0], _numbers[1], _numbers[2]
+
+	return _v, nil
+} // Parse()
+
+func (v *version) Major() uint64      { return v.major }
+func (v *version) Minor() uint64      { return v.minor }
+func (v *version) Patch() uint64      { return v.patch }
+func (v *version) Prerelease() string { return v.prerelease }
+func (v *version) Build() string      { return v.build }
+func (v *version) Prefix() string     { return v.prefix }
+
+// Canonical returns true if this version is of the canonical form
+// vMAJOR.MINOR.PATCH[-PRERELEASE] used by the Go module system.
+func (v *version) Canonical() bool {
+	return v.prefix == "v" && v.build == ""
+} // Canonical()
+
+// Compare returns -1, 0 or 1 if this version has lower, equal or higher
+// precedence than the given version. Build metadata and the version
+// prefix are ignored.
+func (v *version) Compare(other Version) int {
+	// compare the version core
+	for _, _pair := range [][2]uint64{
+		{v.major, other.Major()},
+		{v.minor, other.Minor()},
+		{v.patch, other.Patch()},
+	} {
+		if _pair[0] < _pair[1] {
+			return -1
+		} else if _pair[0] > _pair[1] {
+			return 1
+		}
+	}
+
+	// a version without pre-release identifiers has higher precedence
+	_a, _b := v.prerelease, other.Prerelease()
+	if _a == "" || _b == "" {
+		switch {
+		case _a == _b:
+			return 0
+		case _a == "":
+			return 1
+		default:
+			return -1
+		}
+	}
+
+	// compare the pre-release identifiers in turn
+	//		- numeric identifiers have lower precedence than alphanumeric
+	//		- a larger set of identifiers has higher precedence
+	_aids := strings.Split(_a, ".")
+	_bids := strings.Split(_b, ".")
+	for _i := 0; _i < len(_aids) && _i < len(_bids); _i++ {
+		_x, _y := _aids[_i], _bids[_i]
+		if _x == _y {
+			continue
+		}
+		_xn, _yn := digits(_x), digits(_y)
+		switch {
+		case _xn && _yn:
+			if len(_x) != len(_y) {
+				return sign(len(_x) - len(_y))
+			}
+			return strings.Compare(_x, _y)
+		case _xn:
+			return -1
+		case _yn:
+			return 1
+		default:
+			return strings.Compare(_x, _y)
+		}
+	}
+
+	return sign(len(_aids) - len(_bids))
+} // Compare()
+
+// Next returns the version following this version for the given change.
+// If this is a pre-release version, Next returns the release of this
+// version unless the change requires a higher version. Build metadata
+// is discarded, while the version prefix is retained. If the change is
+// NONE, Next returns this version.
+func (v *version) Next(c Change) Version {
+	if c == NONE {
+		return v
+	}
+	_next := &version{
+		prefix: v.prefix,
+		major:  v.major,
+		minor:  v.minor,
+		patch:  v.patch,
+	}
+
+	// a pre-release is followed by its release, unless the change
+	// requires a more significant version increment
+	if v.prerelease != "" {
+		switch {
+		case c == MAJOR && (v.minor != 0 || v.patch != 0):
+			_next.major, _next.minor, _next.patch = v.major+1, 0, 0
+		case c == MINOR && v.patch != 0:
+			_next.minor, _next.patch = v.minor+1, 0
+		}
+		return _next
+	}
+
+	switch c {
+	case MAJOR:
+		_next.major, _next.minor, _next.patch = v.major+1, 0, 0
+	case MINOR:
+		_next.minor, _next.patch = v.minor+1, 0
+	case PATCH:
+		_next.patch = v.patch + 1
+	}
+
+	return _next
+} // Next()
+
+// String returns the version string.
+func (v *version) String() string {
+	_s := fmt.Sprintf("%s%d.%d.%d", v.prefix, v.major, v.minor, v.patch)
+	if v.prerelease != "" {
+		_s += "-" + v.prerelease
+	}
+	if v.build != "" {
+		_s += "+" + v.build
+	}
+
+	return _s
+} // String()
+
+// identifiers returns true if s is a non-empty, dot-separated list of
+// alphanumeric identifiers. If strict is true, numeric identifiers must
+// not have leading zeros.
+func identifiers(s string, strict bool) bool {
+	for _, _id := range strings.Split(s, ".") {
+		if _id == "" {
+			return false
+		}
+		for _, _c := range _id {
+			if !(_c >= '0' && _c <= '9' ||
+				_c >= 'a' && _c <= 'z' ||
+				_c >= 'A' && _c <= 'Z' ||
+				_c == '-') {
+				return false
+			}
+		}
+		if strict && digits(_id) && !numeric(_id) {
+			return false
+		}
+	}
+
+	return true
+} // identifiers()
+
+// numeric returns true if s is a decimal number without leading zeros
+func numeric(s string) bool {
+	return digits(s) && (s == "0" || s[0] != '0')
+} // numeric()
+
+// digits returns true if s is a non-empty string of decimal digits
+func digits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, _c := range s {
+		if _c < '0' || _c > '9' {
+			return false
+		}
+	}
+
+	return true
+} // digits()
+
+// sign returns -1, 0 or 1 for negative, zero or positive n
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	default:
+		return 0
+	}
+} // sign()
+
+// ensure version implements the Version interface
+var _ Version = &version{}
@@ -0,0 +1,153 @@
+package semver_test
+
+import (
+	"testing"
+
+	"github.com/denormal/go-gitinfo/semver"
+)
+
+func TestParse(t *testing.T) {
+	// ensure valid versions are parsed as expected
+	for _, _test := range []struct {
+		version    string
+		major      uint64
+		minor      uint64
+		patch      uint64
+		prerelease string
+		build      string
+		canonical  bool
+	}{
+		{"v0.0.0", 0, 0, 0, "", "", true},
+		{"1.2.3", 1, 2, 3, "", "", false},
+		{"v1.2.3-rc.1", 1, 2, 3, "rc.1", "", true},
+		{"v1.2.3+build.5", 1, 2, 3, "", "build.5", false},
+		{"v10.20.30-alpha-1.0+001", 10, 20, 30, "alpha-1.0", "001", false},
+	} {
+		_version, _err := semver.Parse(_test.version)
+		if _err != nil {
+			t.Fatalf("%q: unexpected error: %s", _test.version, _err.Error())
+		} else if _version.Major() != _test.major ||
+			_version.Minor() != _test.minor ||
+			_version.Patch() != _test.patch {
+			t.Fatalf(
+				"%q: unexpected version core; got %d.%d.%d",
+				_test.version,
+				_version.Major(), _version.Minor(), _version.Patch(),
+			)
+		} else if _version.Prerelease() != _test.prerelease {
+			t.Fatalf(
+				"%q: unexpected pre-release; expected %q, got %q",
+				_test.version, _test.prerelease, _version.Prerelease(),
+			)
+		} else if _version.Build() != _test.build {
+			t.Fatalf(
+				"%q: unexpected build; expected %q, got %q",
+				_test.version, _test.build, _version.Build(),
+			)
+		} else if _version.Canonical() != _test.canonical {
+			t.Fatalf(
+				"%q: unexpected canonical; expected %v, got %v",
+				_test.version, _test.canonical, _version.Canonical(),
+			)
+		} else if _version.String() != _test.version {
+			t.Fatalf(
+				"unexpected string; expected %q, got %q",
+				_test.version, _version.String(),
+			)
+		}
+	}
+
+	// ensure invalid versions are rejected
+	for _, _invalid := range []string{
+		"", "v", "v1", "v1.2", "v1.2.3.4", "v01.2.3", "v1.2.03", "v1.2.3-",
+		"v1.2.3-01", "v1.2.3-rc..1", "v1.2.3+", "v1.2.3-rc_1", "V1.2.3",
+		"v1.2.x",
+	} {
+		_version, _err := semver.Parse(_invalid)
+		if _err == nil {
+			t.Fatalf(
+				"%q: expected error from Parse(), got %v",
+				_invalid, _version,
+			)
+		}
+	}
+} // TestParse()
+
+func TestCompare(t *testing.T) {
+	// these versions are listed in ascending order of precedence
+	//		- see https://semver.org/#spec-item-11
+	_versions := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.1.0",
+		"v1.10.0",
+		"2.0.0",
+	}
+
+	for _i, _a := range _versions {
+		for _j, _b := range _versions {
+			_expected := 0
+			if _i < _j {
+				_expected = -1
+			} else if _i > _j {
+				_expected = 1
+			}
+
+			_x, _ := semver.Parse(_a)
+			_y, _ := semver.Parse(_b)
+			if _c := _x.Compare(_y); _c != _expected {
+				t.Fatalf(
+					"unexpected comparison of %q and %q; expected %d, got %d",
+					_a, _b, _expected, _c,
+				)
+			}
+		}
+	}
+
+	// build metadata and prefixes are ignored
+	_x, _ := semver.Parse("v1.0.0+a")
+	_y, _ := semver.Parse("1.0.0+b")
+	if _c := _x.Compare(_y); _c != 0 {
+		t.Fatalf("unexpected comparison; expected %d, got %d", 0, _c)
+	}
+} // TestCompare()
+
+func TestNext(t *testing.T) {
+	for _, _test := range []struct {
+		version  string
+		change   semver.Change
+		expected string
+	}{
+		{"v1.2.3", semver.NONE, "v1.2.3"},
+		{"v1.2.3", semver.PATCH, "v1.2.4"},
+		{"v1.2.3", semver.MINOR, "v1.3.0"},
+		{"v1.2.3", semver.MAJOR, "v2.0.0"},
+		{"1.2.3+build", semver.PATCH, "1.2.4"},
+		{"v0.0.0", semver.MINOR, "v0.1.0"},
+		{"v1.0.0-rc.1", semver.NONE, "v1.0.0-rc.1"},
+		{"v1.0.0-rc.1", semver.PATCH, "v1.0.0"},
+		{"v1.0.0-rc.1", semver.MAJOR, "v1.0.0"},
+		{"v1.2.0-rc.1", semver.MINOR, "v1.2.0"},
+		{"v1.2.0-rc.1", semver.MAJOR, "v2.0.0"},
+		{"v1.2.1-rc.1", semver.MINOR, "v1.3.0"},
+	} {
+		_version, _err := semver.Parse(_test.version)
+		if _err != nil {
+			t.Fatalf("%q: unexpected error: %s", _test.version, _err.Error())
+		}
+		_next := _version.Next(_test.change)
+		if _next.String() != _test.expected {
+			t.Fatalf(
+				"%q: unexpected %s version; expected %q, got %q",
+				_test.version, _test.change, _test.expected, _next.String(),
+			)
+		}
+	}
+} // TestNext()
@@ -0,0 +1,142 @@
+package gitinfo
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/denormal/go-gittools"
+)
+
+// ShallowCloneError is the error returned when the history required by an
+// operation is incomplete, because the working copy is a shallow clone.
+type ShallowCloneError struct {
+	// Boundary lists the shallow boundary commits of the clone, whose
+	// parents are missing from the clone.
+	Boundary []string
+}
+
+// Error returns the error message, describing the shallow clone.
+func (e *ShallowCloneError) Error() string {
+	return "history unavailable for shallow clone; fetch with --unshallow"
+} // Error()
+
+// IsShallow returns true if the working copy is a shallow clone, with
+// incomplete history. An error is returned if the GitInfo instance was
+// initialised for a path not within a working copy, or there is a problem
+// determining the shallow state.
+func (g *gitinfo) IsShallow() (bool, error) {
+	_boundary, _err := g.boundary()
+	if _err != nil {
+		return false, _err
+	}
+
+	return len(_boundary) != 0, nil
+} // IsShallow()
+
+// Shallow returns the shallow boundary commits of the working copy, whose
+// parents are missing from the clone. If the working copy is not a shallow
+// clone, Shallow returns an empty list. An error is returned if the GitInfo
+// instance was initialised for a path not within a working copy, or there
+// is a problem determining the shallow boundary.
+func (g *gitinfo) Shallow() ([]Commit, error) {
+	_boundary, _err := g.boundary()
+	if _err != nil {
+		return nil, _err
+	}
+
+	_commits := make([]Commit, 0, len(_boundary))
+	for _, _hash := range _boundary {
+		_commits = append(_commits, newCommit(g.config, _hash))
+	}
+
+	return _commits, nil
+} // Shallow()
+
+// PartialCloneFilter returns the object filter of a partial clone (e.g.
+// "blob:none"), or the empty string if the working copy is not a partial
+// clone. An error is returned if the GitInfo instance was initialised for
+// a path not within a working copy.
+func (g *gitinfo) PartialCloneFilter() (string, error) {
+	_root := g.Root()
+	if _root == "" {
+		return "", MissingWorkingCopyError
+	}
+
+	// the filter is recorded against the promisor remote
+	//		- git exits with an error if there are no matching entries
+	_output, _err := gittools.RunInPath(
+		_root, "config", "--get-regexp", `^remote\..*\.partialclonefilter$`,
+	)
+	if _err != nil {
+		return "", nil
+	}
+	for _, _line := range lines(_output) {
+		_parts := strings.SplitN(_line, " ", 2)
+		if len(_parts) == 2 && _parts[1] != "" {
+			return _parts[1], nil
+		}
+	}
+
+	return "", nil
+} // PartialCloneFilter()
+
+// boundary returns the shallow boundary commit hashes of the working copy,
+// as recorded in the repository's "shallow" file, or nil if the working copy
+// is not a shallow clone
+func (g *gitinfo) boundary() ([]string, error) {
+	_root := g.Root()
+	if _root == "" {
+		return nil, MissingWorkingCopyError
+	}
+
+	// locate the shallow file
+	//		- relative paths are relative to the working copy root
+	_output, _err := revparse(_root, "--git-path", "shallow")
+	if _err != nil {
+		return nil, _err
+	}
+	_path := strings.TrimSpace(string(_output))
+	if !filepath.IsAbs(_path) {
+		_path = filepath.Join(_root, _path)
+	}
+
+	_bytes, _err := ioutil.ReadFile(_path)
+	if os.IsNotExist(_err) {
+		return nil, nil
+	} else if _err != nil {
+		return nil, _err
+	}
+
+	return lines(_bytes), nil
+} // boundary()
+
+// complete returns a ShallowCloneError if the history of the commits
+// given by the "git rev-list" arguments is truncated by the shallow boundary
+// of the working copy, or nil if the history is complete
+func (g *gitinfo) complete(args ...string) error {
+	_boundary, _err := g.boundary()
+	if _err != nil || len(_boundary) == 0 {
+		return _err
+	}
+
+	// list the commits, and look for any boundary commits
+	_output, _err := gittools.RunInPath(
+		g.Root(), append([]string{"rev-list"}, args...)...,
+	)
+	if _err != nil {
+		return _err
+	}
+	_set := make(map[string]bool, len(_boundary))
+	for _, _hash := range _boundary {
+		_set[_hash] = true
+	}
+	for _, _hash := range lines(_output) {
+		if _set[_hash] {
+			return &ShallowCloneError{Boundary: _boundary}
+		}
+	}
+
+	return nil
+} // complete()
@@ -0,0 +1,159 @@
+package gitinfo_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/denormal/go-gitinfo"
+	"github.com/denormal/go-gittools"
+)
+
+func TestShallow(t *testing.T) {
+	// if we don't have git installed, then skip this test
+	if !gittools.HasGit() {
+		t.Skip("git not installed")
+	}
+
+	_dir := repository(t)
+	defer os.RemoveAll(_dir)
+	write := func(name string) {
+		_err := ioutil.WriteFile(filepath.Join(_dir, name), []byte(name), 0644)
+		if _err != nil {
+			t.Fatalf("unable to write %s: %s", name, _err.Error())
+		}
+	}
+	write("one")
+	commit(t, _dir, "2024-01-01T00:00:00Z", "feat: one")
+	write("two")
+	commit(t, _dir, "2024-01-02T00:00:00Z", "fix: two")
+	write("three")
+	_head := commit(t, _dir, "2024-01-03T00:00:00Z", "fix: three")
+	_parent := strings.TrimSpace(git(t, _dir, "rev-parse", "HEAD~1"))
+	git(t, _dir, "config", "uploadpack.allowfilter", "true")
+
+	// the original repository is complete
+	clone(t, _dir, false, nil, "")
+
+	// shallow clones report their boundary, and history-dependent
+	// operations report an error
+	_shallow := filepath.Join(_dir, "shallow")
+	git(t, _dir, "clone", "-q", "--depth", "2", "file://"+_dir, _shallow)
+	_info := clone(t, _shallow, true, []string{_parent}, "")
+
+	_, _err := _info.Commits("", "")
+	shallowError(t, "Commits()", _err, _parent)
+	_, _err = _info.Changelog("", "")
+	shallowError(t, "Changelog()", _err, _parent)
+	_, _err = _info.CommitCount(gitinfo.CountOptions{})
+	shallowError(t, "CommitCount()", _err, _parent)
+	_, _err = _info.PseudoVersion()
+	shallowError(t, "PseudoVersion()", _err, _parent)
+
+	// history within the shallow boundary is available
+	_commits, _err := _info.Commits("HEAD~1", "HEAD")
+	if _err != nil {
+		t.Fatalf("unexpected error from Commits(): %s", _err.Error())
+	} else if len(_commits) != 1 || _commits[0].String() != _head {
+		t.Fatalf("unexpected commits; expected [%s], got %v", _head, _commits)
+	}
+
+	// partial clones report their filter
+	_partial := filepath.Join(_dir, "partial")
+	git(t, _dir,
+		"clone", "-q", "--filter=blob:none", "file://"+_dir, _partial,
+	)
+	_info = clone(t, _partial, false, nil, "blob:none")
+	_count, _err := _info.CommitCount(gitinfo.CountOptions{})
+	if _err != nil {
+		t.Fatalf("unexpected error from CommitCount(): %s", _err.Error())
+	} else if _count != 3 {
+		t.Fatalf("unexpected count; expected %d, got %d", 3, _count)
+	}
+
+	// Build() has no clone details
+	_, _err = gitinfo.Build(nil).IsShallow()
+	if _err != gitinfo.MissingWorkingCopyError {
+		t.Fatalf(
+			"unexpected error from IsShallow(); expected %v, got %v",
+			gitinfo.MissingWorkingCopyError, _err,
+		)
+	}
+} // TestShallow()
+
+//
+// helper functions
+//
+
+// clone ensures the working copy at the given path has the expected shallow
+// boundary and partial clone filter
+func clone(
+	t *testing.T,
+	dir string,
+	shallow bool,
+	boundary []string,
+	filter string,
+) gitinfo.GitInfo {
+	_info, _err := gitinfo.NewWithPath(dir)
+	if _err != nil {
+		t.Fatalf("unexpected error from NewWithPath(): %s", _err.Error())
+	}
+
+	_shallow, _err := _info.IsShallow()
+	if _err != nil {
+		t.Fatalf("unexpected error from IsShallow(): %s", _err.Error())
+	} else if _shallow != shallow {
+		t.Fatalf(
+			"%s: unexpected IsShallow(); expected %v, got %v",
+			dir, shallow, _shallow,
+		)
+	}
+
+	_commits, _err := _info.Shallow()
+	if _err != nil {
+		t.Fatalf("unexpected error from Shallow(): %s", _err.Error())
+	}
+	_hashes := make([]string, 0, len(_commits))
+	for _, _commit := range _commits {
+		_hashes = append(_hashes, _commit.String())
+	}
+	if strings.Join(_hashes, " ") != strings.Join(boundary, " ") {
+		t.Fatalf(
+			"%s: unexpected Shallow(); expected %v, got %v",
+			dir, boundary, _hashes,
+		)
+	}
+
+	_filter, _err := _info.PartialCloneFilter()
+	if _err != nil {
+		t.Fatalf(
+			"unexpected error from PartialCloneFilter(): %s", _err.Error(),
+		)
+	} else if _filter != filter {
+		t.Fatalf(
+			"%s: unexpected PartialCloneFilter(); expected %q, got %q",
+			dir, filter, _filter,
+		)
+	}
+
+	return _info
+} // clone()
+
+// shallowError ensures the given error is a ShallowCloneError with the
+// given boundary
+func shallowError(t *testing.T, name string, err error, boundary string) {
+	_shallow, _ok := err.(*gitinfo.ShallowCloneError)
+	if !_ok {
+		t.Fatalf(
+			"unexpected error from %s; expected %T, got %v",
+			name, _shallow, err,
+		)
+	} else if len(_shallow.Boundary) != 1 || _shallow.Boundary[0] != boundary {
+		t.Fatalf(
+			"unexpected boundary from %s; expected [%s], got %v",
+			name, boundary, _shallow.Boundary,
+		)
+	}
+} // shallowError()
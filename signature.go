@@ -0,0 +1,183 @@
+package gitinfo
+
+import (
+	"strings"
+
+	"github.com/denormal/go-gittools"
+)
+
+// the types of commit signature
+const (
+	SIGNATURE_GPG  = "gpg"
+	SIGNATURE_SSH  = "ssh"
+	SIGNATURE_X509 = "x509"
+)
+
+// SignatureStatus is git's validity status for a commit signature, as
+// reported by the "%G?" format placeholder of "git log".
+type SignatureStatus byte
+
+const (
+	SIGNATURE_GOOD         SignatureStatus = 'G' // good signature
+	SIGNATURE_BAD          SignatureStatus = 'B' // bad signature
+	SIGNATURE_UNKNOWN      SignatureStatus = 'U' // good, with unknown validity
+	SIGNATURE_EXPIRED      SignatureStatus = 'X' // good, but expired
+	SIGNATURE_EXPIRED_KEY  SignatureStatus = 'Y' // good, made by an expired key
+	SIGNATURE_REVOKED      SignatureStatus = 'R' // good, made by a revoked key
+	SIGNATURE_UNVERIFIABLE SignatureStatus = 'E' // cannot be checked
+	SIGNATURE_NONE         SignatureStatus = 'N' // no signature
+)
+
+// the headers of the signature within the raw commit object, for SHA-1 and
+// SHA-256 repositories, and the armour markers identifying the signature type
+var (
+	_GPGSIG = []string{"gpgsig ", "gpgsig-sha256 "}
+	_ARMOUR = map[string]string{
+		"-----BEGIN PGP SIGNATURE-----":  SIGNATURE_GPG,
+		"-----BEGIN SSH SIGNATURE-----":  SIGNATURE_SSH,
+		"-----BEGIN SIGNED MESSAGE-----": SIGNATURE_X509,
+	}
+)
+
+// String returns the name of the signature status.
+func (s SignatureStatus) String() string {
+	switch s {
+	case SIGNATURE_GOOD:
+		return "good"
+	case SIGNATURE_BAD:
+		return "bad"
+	case SIGNATURE_UNKNOWN:
+		return "unknown"
+	case SIGNATURE_EXPIRED:
+		return "expired"
+	case SIGNATURE_EXPIRED_KEY:
+		return "expired key"
+	case SIGNATURE_REVOKED:
+		return "revoked"
+	case SIGNATURE_UNVERIFIABLE:
+		return "unverifiable"
+	default:
+		return "no signature"
+	}
+} // String()
+
+// Signature represents the signature of a git commit.
+type Signature interface {
+	// Type returns the type of the signature; one of SIGNATURE_GPG,
+	// SIGNATURE_SSH or SIGNATURE_X509, or the empty string if the commit
+	// is not signed.
+	Type() string
+
+	// Status returns git's validity status for the signature.
+	Status() SignatureStatus
+
+	// Valid returns true if git reports the signature as good. Signatures
+	// of unknown validity, such as those made by untrusted keys, are not
+	// considered valid.
+	Valid() bool
+
+	// Key returns the key used to sign the commit, or the empty string if
+	// this is not known.
+	Key() string
+
+	// Fingerprint returns the fingerprint of the key used to sign the
+	// commit, or the empty string if this is not known.
+	Fingerprint() string
+
+	// Signer returns the name of the signer, or the empty string if this
+	// is not known.
+	Signer() string
+}
+
+type signature struct {
+	typ         string
+	status      SignatureStatus
+	key         string
+	fingerprint string
+	signer      string
+}
+
+func (s *signature) Type() string            { return s.typ }
+func (s *signature) Status() SignatureStatus { return s.status }
+func (s *signature) Key() string             { return s.key }
+func (s *signature) Fingerprint() string     { return s.fingerprint }
+func (s *signature) Signer() string          { return s.signer }
+
+// Valid returns true if git reports the signature as good.
+func (s *signature) Valid() bool {
+	return s.status == SIGNATURE_GOOD
+} // Valid()
+
+// Signature returns the signature details of the commit. If the commit is
+// not signed, or the commit details are not available, the signature status
+// is SIGNATURE_NONE. An error is returned if there is a problem extracting
+// the signature.
+func (c *commit) Signature() (Signature, error) {
+	c.signed.Do(func() {
+		c.signature = &signature{status: SIGNATURE_NONE}
+
+		// do we have a working copy to examine?
+		if c.config == nil || c.config.Root() == "" || c.commit == "" {
+			return
+		}
+		_root := c.config.Root()
+
+		// determine the signature type from the raw commit object
+		_raw, _err := gittools.RunInPath(_root, "cat-file", "commit", c.commit)
+		if _err != nil {
+			c.signatureErr = _err
+			return
+		}
+		_type := ""
+	_headers:
+		for _, _line := range strings.Split(string(_raw), "\n") {
+			if _line == "" {
+				// we have reached the end of the commit headers
+				break
+			}
+			for _, _header := range _GPGSIG {
+				if strings.HasPrefix(_line, _header) {
+					_type = _ARMOUR[strings.TrimPrefix(_line, _header)]
+					break _headers
+				}
+			}
+		}
+		if _type == "" {
+			return
+		}
+
+		// extract the signature details
+		_output, _err := gittools.RunInPath(
+			_root, "show", "-s", "--format=%G?%x1f%GK%x1f%GF%x1f%GS", c.commit,
+		)
+		if _err != nil {
+			c.signatureErr = _err
+			return
+		}
+		_fields := strings.Split(strings.TrimRight(string(_output), "\n"), "\x1f")
+		if len(_fields) != 4 || len(_fields[0]) != 1 {
+			return
+		}
+
+		// the commit is signed, but if git reports there is no signature,
+		// then git is unable to verify it (e.g. it is not configured for
+		// verifying SSH signatures)
+		_status := SignatureStatus(_fields[0][0])
+		if _status == SIGNATURE_NONE {
+			_status = SIGNATURE_UNVERIFIABLE
+		}
+
+		c.signature = &signature{
+			typ:         _type,
+			status:      _status,
+			key:         _fields[1],
+			fingerprint: _fields[2],
+			signer:      _fields[3],
+		}
+	})
+
+	return c.signature, c.signatureErr
+} // Signature()
+
+// ensure signature implements the Signature interface
+var _ Signature = &signature{}
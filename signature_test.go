@@ -0,0 +1,184 @@
+package gitinfo_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/denormal/go-gitinfo"
+	"github.com/denormal/go-gittools"
+)
+
+func TestSignature(t *testing.T) {
+	// if we don't have git installed, then skip this test
+	if !gittools.HasGit() {
+		t.Skip("git not installed")
+	}
+	// we sign commits with SSH keys, so we require ssh-keygen
+	_keygen, _err := exec.LookPath("ssh-keygen")
+	if _err != nil {
+		t.Skip("ssh-keygen not installed")
+	}
+
+	_dir := repository(t)
+	defer os.RemoveAll(_dir)
+
+	// generate the signing key
+	//		- the key is kept outside the working copy
+	_keys, _err := ioutil.TempDir("", "")
+	if _err != nil {
+		t.Fatalf("unable to create temporary directory: %s", _err.Error())
+	}
+	defer os.RemoveAll(_keys)
+	_key := filepath.Join(_keys, "key")
+	_output, _err := exec.Command(
+		_keygen, "-q", "-t", "ed25519", "-N", "", "-C", "gitinfo", "-f", _key,
+	).CombinedOutput()
+	if _err != nil {
+		t.Fatalf("unable to generate SSH key: %s: %s", _err.Error(), _output)
+	}
+	_public, _err := ioutil.ReadFile(_key + ".pub")
+	if _err != nil {
+		t.Fatalf("unable to read SSH public key: %s", _err.Error())
+	}
+
+	// configure the repository for SSH signing
+	git(t, _dir, "config", "gpg.format", "ssh")
+	git(t, _dir, "config", "user.signingkey", _key+".pub")
+
+	// an unsigned commit has no signature
+	commit(t, _dir, "2024-01-01T00:00:00Z", "unsigned")
+	signature(t, _dir, "", gitinfo.SIGNATURE_NONE, "")
+
+	// a signed commit cannot be verified without the allowed signers
+	git(t, _dir,
+		"-c", "user.name=gitinfo", "-c", "user.email=gitinfo@example.com",
+		"commit", "-q", "--allow-empty", "-S", "-m", "signed",
+	)
+	signature(t, _dir,
+		gitinfo.SIGNATURE_SSH, gitinfo.SIGNATURE_UNVERIFIABLE, "",
+	)
+
+	// with the allowed signers, the signature should be good
+	_allowed := filepath.Join(_keys, "allowed_signers")
+	_err = ioutil.WriteFile(
+		_allowed,
+		[]byte("gitinfo@example.com "+string(_public)),
+		0644,
+	)
+	if _err != nil {
+		t.Fatalf("unable to write allowed signers: %s", _err.Error())
+	}
+	git(t, _dir, "config", "gpg.ssh.allowedSignersFile", _allowed)
+	signature(t, _dir,
+		gitinfo.SIGNATURE_SSH, gitinfo.SIGNATURE_GOOD, "gitinfo@example.com",
+	)
+
+	// a commit signed by a key that is not allowed should not be valid
+	_other := filepath.Join(_keys, "other")
+	_output, _err = exec.Command(
+		_keygen, "-q", "-t", "ed25519", "-N", "", "-C", "other", "-f", _other,
+	).CombinedOutput()
+	if _err != nil {
+		t.Fatalf("unable to generate SSH key: %s: %s", _err.Error(), _output)
+	}
+	git(t, _dir,
+		"-c", "user.name=gitinfo", "-c", "user.email=gitinfo@example.com",
+		"-c", "user.signingkey="+_other+".pub",
+		"commit", "-q", "--allow-empty", "-S", "-m", "other",
+	)
+	_signature := signature(
+		t, _dir, gitinfo.SIGNATURE_SSH, gitinfo.SIGNATURE_UNKNOWN, "",
+	)
+	if _signature.Valid() {
+		t.Fatalf(
+			"unexpected valid signature; status %s", _signature.Status(),
+		)
+	}
+
+	// SHA-256 repositories use the gpgsig-sha256 header
+	//		- skip this check if git does not support SHA-256 repositories
+	_sha256 := _dir + "-sha256"
+	defer os.RemoveAll(_sha256)
+	_, _err = gittools.RunInPath(
+		_dir, "init", "-q", "--object-format=sha256", _sha256,
+	)
+	if _err != nil {
+		t.Logf("unable to create SHA-256 repository: %s", _err.Error())
+		return
+	}
+	git(t, _sha256, "config", "gpg.format", "ssh")
+	git(t, _sha256, "config", "user.signingkey", _key+".pub")
+	git(t, _sha256, "config", "gpg.ssh.allowedSignersFile", _allowed)
+	git(t, _sha256,
+		"-c", "user.name=gitinfo", "-c", "user.email=gitinfo@example.com",
+		"commit", "-q", "--allow-empty", "-S", "-m", "signed",
+	)
+	signature(t, _sha256,
+		gitinfo.SIGNATURE_SSH, gitinfo.SIGNATURE_GOOD, "gitinfo@example.com",
+	)
+} // TestSignature()
+
+//
+// helper functions
+//
+
+// signature ensures the signature of HEAD has the expected type, status
+// and signer, and that Map() reports the signature validity
+func signature(
+	t *testing.T,
+	dir, typ string,
+	status gitinfo.SignatureStatus,
+	signer string,
+) gitinfo.Signature {
+	_info, _err := gitinfo.NewWithPath(dir)
+	if _err != nil {
+		t.Fatalf("unexpected error from NewWithPath(): %s", _err.Error())
+	}
+	_commit, _err := _info.Commit()
+	if _err != nil {
+		t.Fatalf("unexpected error from Commit(): %s", _err.Error())
+	}
+	_signature, _err := _commit.Signature()
+	if _err != nil {
+		t.Fatalf("unexpected error from Signature(): %s", _err.Error())
+	} else if _signature.Type() != typ {
+		t.Fatalf(
+			"unexpected signature type; expected %q, got %q",
+			typ, _signature.Type(),
+		)
+	} else if _signature.Status() != status {
+		t.Fatalf(
+			"unexpected signature status; expected %s, got %s",
+			status, _signature.Status(),
+		)
+	} else if _signature.Signer() != signer {
+		t.Fatalf(
+			"unexpected signer; expected %q, got %q",
+			signer, _signature.Signer(),
+		)
+	}
+
+	// good signatures should report the key fingerprint
+	if _signature.Status() == gitinfo.SIGNATURE_GOOD &&
+		!strings.HasPrefix(_signature.Fingerprint(), "SHA256:") {
+		t.Fatalf(
+			"unexpected signature fingerprint %q", _signature.Fingerprint(),
+		)
+	}
+
+	// ensure the map reports the signature validity
+	_signed := _info.Map()[gitinfo.COMMIT_SIGNED]
+	if _signed != strconv.FormatBool(_signature.Valid()) {
+		t.Fatalf(
+			"unexpected %s; expected %v, got %q",
+			gitinfo.COMMIT_SIGNED, _signature.Valid(), _signed,
+		)
+	}
+
+	return _signature
+} // signature()
@@ -0,0 +1,108 @@
+package gitinfo
+
+import (
+	"log/slog"
+)
+
+// DESCRIBE is the key of the slog attribute describing the HEAD commit,
+// added by NewLogHandler
+const DESCRIBE = "describe"
+
+// NewLogHandler returns a slog.Handler that adds the commit, branch,
+// modified state and description of the HEAD commit of the given GitInfo to
+// every record handled by h, as the attributes of the named group. If group
+// is "", the attributes are added to the top level of each record.
+// Attributes whose values are not known are omitted. The description is
+// the pseudo-version of the HEAD commit if it can be determined, otherwise
+// the abbreviated commit hash, with "-dirty" appended if the working copy is
+// modified. Unlike logging a GitInfo directly, the attributes are determined
+// once, when NewLogHandler is called.
+func NewLogHandler(h slog.Handler, gi GitInfo, group string) slog.Handler {
+	_attrs := gi.LogValue().Group()
+	if _describe := describe(gi); _describe != "" {
+		_attrs = append(_attrs, slog.String(DESCRIBE, _describe))
+	}
+
+	// groups without a key are inlined by slog
+	return h.WithAttrs([]slog.Attr{
+		{Key: group, Value: slog.GroupValue(_attrs...)},
+	})
+} // NewLogHandler()
+
+// LogValue returns the commit, branch and modified state of the working
+// copy as a slog group value.
+func (g *gitinfo) LogValue() slog.Value { return logValue(g) }
+
+// LogValue returns the commit, branch and modified state of the build as a
+// slog group value.
+func (b build) LogValue() slog.Value { return logValue(&b) }
+
+// LogValue returns the commit hash as a slog value.
+func (c *commit) LogValue() slog.Value { return slog.StringValue(c.commit) }
+
+// LogValue returns the name and e-mail address of the git user as a slog
+// group value.
+func (u *user) LogValue() slog.Value { return userValue(u) }
+
+// LogValue returns the name and e-mail address of the user as a slog group
+// value.
+func (p *person) LogValue() slog.Value { return userValue(p) }
+
+// logValue returns the commit, branch and modified state of the given
+// GitInfo as a slog group value, omitting values that are not known
+func logValue(gi GitInfo) slog.Value {
+	_attrs := make([]slog.Attr, 0, 3)
+	_commit, _err := gi.Commit()
+	if _err == nil && _commit != nil && _commit.String() != "" {
+		_attrs = append(_attrs, slog.Any(COMMIT, _commit))
+	}
+	if _branch, _err := gi.Branch(); _err == nil && _branch != "" {
+		_attrs = append(_attrs, slog.String(BRANCH, _branch))
+	}
+
+	// the modified state is only meaningful if we have a commit
+	if len(_attrs) > 0 && _attrs[0].Key == COMMIT {
+		if _modified, _err := gi.Modified(); _err == nil {
+			_attrs = append(_attrs, slog.Bool(MODIFIED, _modified))
+		}
+	}
+
+	return slog.GroupValue(_attrs...)
+} // logValue()
+
+// userValue returns the name and e-mail address of the given user as a slog
+// group value, omitting values that are not defined
+func userValue(u User) slog.Value {
+	_attrs := make([]slog.Attr, 0, 2)
+	if _name := u.Name(); _name != "" {
+		_attrs = append(_attrs, slog.String("name", _name))
+	}
+	if _email := u.Email(); _email != "" {
+		_attrs = append(_attrs, slog.String("email", _email))
+	}
+
+	return slog.GroupValue(_attrs...)
+} // userValue()
+
+// describe returns the description of the HEAD commit of the given GitInfo;
+// the pseudo-version of the commit if it can be determined, otherwise the
+// abbreviated commit hash, with "-dirty" appended if the working copy is
+// modified, or the empty string if the commit is not known
+func describe(gi GitInfo) string {
+	_commit, _err := gi.Commit()
+	if _err != nil || _commit == nil || _commit.String() == "" {
+		return ""
+	}
+
+	// fall back to the commit hash if we cannot determine the version
+	//		- e.g. for shallow clones, or GitInfo created by Build()
+	_describe, _err := gi.PseudoVersion()
+	if _err != nil || _describe == "" {
+		_describe = _commit.Prefix(_PSEUDO_HASH)
+	}
+	if _modified, _ := gi.Modified(); _modified {
+		_describe = _describe + "-dirty"
+	}
+
+	return _describe
+} // describe()
@@ -0,0 +1,125 @@
+package gitinfo_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/denormal/go-gitinfo"
+	"github.com/denormal/go-gittools"
+)
+
+// the git information logged by the tests
+var _LOGGED = map[string]string{
+	gitinfo.BRANCH:     "main",
+	gitinfo.COMMIT:     "0123456789abcdef0123456789abcdef01234567",
+	gitinfo.MODIFIED:   "true",
+	gitinfo.USER_NAME:  "user",
+	gitinfo.USER_EMAIL: "",
+}
+
+func TestLogValue(t *testing.T) {
+	_info := gitinfo.Build(_LOGGED)
+	_commit, _ := _info.Commit()
+
+	// are the git information, commit and user logged as expected?
+	logged(t, func(l *slog.Logger) {
+		l.Info("test",
+			"git", _info,
+			"commit", _commit,
+			"user", _info.User(),
+		)
+	}, map[string]interface{}{
+		"git": map[string]interface{}{
+			"commit":   _LOGGED[gitinfo.COMMIT],
+			"branch":   "main",
+			"modified": true,
+		},
+		"commit": _LOGGED[gitinfo.COMMIT],
+		"user":   map[string]interface{}{"name": "user"},
+	})
+
+	// unknown values are omitted
+	logged(t, func(l *slog.Logger) {
+		l.Info("test", "git", gitinfo.Build(nil))
+	}, map[string]interface{}{})
+} // TestLogValue()
+
+func TestNewLogHandler(t *testing.T) {
+	_info := gitinfo.Build(_LOGGED)
+
+	// are the attributes added to every record in the named group?
+	logged(t, func(l *slog.Logger) {
+		_handler := gitinfo.NewLogHandler(l.Handler(), _info, "git")
+		slog.New(_handler).WithGroup("request").Info("test", "id", 1)
+	}, map[string]interface{}{
+		"git": map[string]interface{}{
+			"commit":   _LOGGED[gitinfo.COMMIT],
+			"branch":   "main",
+			"modified": true,
+			"describe": "0123456789ab-dirty",
+		},
+		"request": map[string]interface{}{"id": 1.0},
+	})
+
+	// if we don't have git installed, then skip the working copy tests
+	if !gittools.HasGit() {
+		t.Skip("git not installed")
+	}
+
+	// tagged working copies are described by their tag
+	_dir := repository(t)
+	defer os.RemoveAll(_dir)
+	_commit := commit(t, _dir, _WHEN, "initial", "v1.0.0")
+	git(t, _dir, "checkout", "-q", "-B", "main")
+	_err := ioutil.WriteFile(
+		filepath.Join(_dir, "README"), []byte("modified\n"), 0644,
+	)
+	if _err != nil {
+		t.Fatalf("unable to write README: %s", _err.Error())
+	}
+	_info, _err = gitinfo.NewWithPath(_dir)
+	if _err != nil {
+		t.Fatalf("unexpected error from NewWithPath(): %s", _err.Error())
+	}
+
+	// attributes without a group are added to the top level
+	logged(t, func(l *slog.Logger) {
+		slog.New(gitinfo.NewLogHandler(l.Handler(), _info, "")).Info("test")
+	}, map[string]interface{}{
+		"commit":   _commit,
+		"branch":   "main",
+		"modified": true,
+		"describe": "v1.0.0-dirty",
+	})
+} // TestNewLogHandler()
+
+// logged logs using a JSON logger, and compares the logged attributes,
+// other than the time, level and message, to those expected
+func logged(
+	t *testing.T,
+	log func(*slog.Logger),
+	expected map[string]interface{},
+) {
+	_buffer := new(bytes.Buffer)
+	log(slog.New(slog.NewJSONHandler(_buffer, nil)))
+
+	_got := make(map[string]interface{})
+	_err := json.Unmarshal(_buffer.Bytes(), &_got)
+	if _err != nil {
+		t.Fatalf("unable to decode %q: %s", _buffer.String(), _err.Error())
+	}
+	for _, _key := range []string{slog.TimeKey, slog.LevelKey, slog.MessageKey} {
+		delete(_got, _key)
+	}
+	if !reflect.DeepEqual(_got, expected) {
+		t.Fatalf("unexpected log attributes; expected %v, got %v",
+			expected, _got,
+		)
+	}
+} // logged()
@@ -0,0 +1,47 @@
+package stale
+
+import (
+	"strings"
+
+	"github.com/denormal/go-gitinfo"
+	"golang.org/x/tools/go/analysis"
+)
+
+// Analyzer reports generated git information, as written by "gitinfo -X",
+// that differs from the working copy containing the generated file. Files
+// outside a working copy (such as those of the module cache) are ignored.
+var Analyzer = &analysis.Analyzer{
+	Name: "gitinfo",
+	Doc:  "report stale git information generated by gitinfo -X",
+	Run:  run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	for _, _file := range pass.Files {
+		_call, _generated := Generated(_file)
+		if _call == nil {
+			continue
+		}
+
+		// compare the generated git information with the working copy
+		_filename := pass.Fset.Position(_file.Pos()).Filename
+		_diffs, _err := Compare(_filename, _generated, false)
+		if _err == gitinfo.MissingWorkingCopyError {
+			continue
+		} else if _err != nil {
+			pass.Reportf(_call.Pos(),
+				"unable to check git information: %s", _err.Error(),
+			)
+		} else if len(_diffs) != 0 {
+			_strings := make([]string, 0, len(_diffs))
+			for _, _diff := range _diffs {
+				_strings = append(_strings, _diff.String())
+			}
+			pass.Reportf(_call.Pos(),
+				"stale git information: %s", strings.Join(_strings, "; "),
+			)
+		}
+	}
+
+	return nil, nil
+} // run()
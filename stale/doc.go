@@ -0,0 +1,13 @@
+/*
+Package stale detects generated git information, as written by
+"gitinfo -X", that no longer matches the working copy it was generated
+from. Check compares a generated file against the live git information,
+while Analyzer reports stale files for go/analysis drivers, such as those
+built with singlechecker or multichecker:
+
+	_diffs, _err := stale.Check("git.go", false)
+	for _, _diff := range _diffs {
+		fmt.Println(_diff)
+	}
+*/
+package stale
@@ -0,0 +1,9 @@
+package stale
+
+import (
+	"errors"
+)
+
+var (
+	MissingBuildError = errors.New("no generated gitinfo.Build() call found")
+)
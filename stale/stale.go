@@ -0,0 +1,281 @@
+package stale
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/denormal/go-gitinfo"
+)
+
+// the import path of go-gitinfo, which we could hard-code, but instead we
+// determine using reflection
+var _IMPORT = reflect.TypeOf((*gitinfo.GitInfo)(nil)).Elem().PkgPath()
+
+// IGNORED lists the fields that describe the environment of the generation,
+// rather than the working copy, and are not compared by default.
+var IGNORED = []string{
+	gitinfo.CI_BRANCH,
+	gitinfo.CI_PIPELINE,
+	gitinfo.CI_PROVIDER,
+	gitinfo.CI_PULL_REQUEST,
+	gitinfo.CI_URL,
+	gitinfo.EDITOR,
+	gitinfo.GIT,
+	gitinfo.PATH,
+	gitinfo.ROOT,
+	gitinfo.USER_EMAIL,
+	gitinfo.USER_NAME,
+}
+
+// Difference describes a generated git information field whose value
+// differs from the live git information.
+type Difference struct {
+	Field     string // the field name
+	Generated string // the value in the generated file
+	Live      string // the value of the working copy
+}
+
+// String returns the description of the difference.
+func (d Difference) String() string {
+	return fmt.Sprintf(
+		"%s: generated %q, live %q", d.Field, d.Generated, d.Live,
+	)
+} // String()
+
+// Check parses the named Go source file, as generated by "gitinfo -X", and
+// compares its git information with that of the working copy containing
+// the file, returning the differences ordered by field name. Unless all is
+// true, the IGNORED fields are not compared. Check returns
+// MissingBuildError if the file does not contain generated git
+// information, or an error if the file or the working copy cannot be read.
+func Check(filename string, all bool) ([]Difference, error) {
+	_file, _err := parser.ParseFile(token.NewFileSet(), filename, nil, 0)
+	if _err != nil {
+		return nil, _err
+	}
+	_, _generated := Generated(_file)
+	if _generated == nil {
+		return nil, MissingBuildError
+	}
+
+	return Compare(filename, _generated, all)
+} // Check()
+
+// Compare compares the generated git information of the named file with
+// the git information of the working copy containing the file, returning
+// the differences ordered by field name. Fields of the generated
+// information that are not reported by the working copy (such as the
+// embedded patch) are ignored, as are the IGNORED fields unless all is
+// true. The file itself is excluded when determining whether the working
+// copy is modified, and the generated count is compared with the count of
+// the working copy for the options recorded by the count.options field. An
+// error is returned if the working copy cannot be read.
+func Compare(
+	filename string,
+	generated map[string]string,
+	all bool,
+) ([]Difference, error) {
+	// load the git information of the file's working copy
+	_path, _err := filepath.Abs(filename)
+	if _err == nil {
+		_path, _err = filepath.EvalSymlinks(_path)
+	}
+	if _err != nil {
+		return nil, _err
+	}
+	_info, _err := gitinfo.NewWithPath(filepath.Dir(_path))
+	if _err != nil {
+		return nil, _err
+	} else if _info.Root() == "" {
+		return nil, gitinfo.MissingWorkingCopyError
+	}
+
+	// the generated file does not modify the working copy
+	_relative, _err := filepath.Rel(_info.Root(), _path)
+	if _err != nil {
+		return nil, _err
+	}
+	_info, _err = gitinfo.NewWithOptions(_info.Path(), gitinfo.ModifiedOptions{
+		Exclude: []string{":(literal)" + filepath.ToSlash(_relative)},
+	})
+	if _err != nil {
+		return nil, _err
+	}
+
+	// which fields should we ignore?
+	_ignored := make(map[string]bool)
+	if !all {
+		for _, _field := range IGNORED {
+			_ignored[_field] = true
+		}
+	}
+
+	// compare the generated and live fields
+	//		- the generated count may be restricted by its recorded options
+	_live := _info.Map()
+	if _, _ok := generated[gitinfo.COUNT]; _ok {
+		_options := gitinfo.ParseCountOptions(generated[gitinfo.COUNT_OPTIONS])
+		if _options != (gitinfo.CountOptions{}) {
+			_live[gitinfo.COUNT] = ""
+			_count, _err := _info.CommitCount(_options)
+			if _err == nil {
+				_live[gitinfo.COUNT] = strconv.Itoa(_count)
+			}
+		}
+	}
+	_diffs := make([]Difference, 0)
+	for _field, _generated := range generated {
+		_value, _ok := _live[_field]
+		if !_ok || _ignored[_field] || _value == _generated {
+			continue
+		}
+		_diffs = append(_diffs, Difference{_field, _generated, _value})
+	}
+	sort.Slice(_diffs, func(i, j int) bool {
+		return _diffs[i].Field < _diffs[j].Field
+	})
+
+	return _diffs, nil
+} // Compare()
+
+// Generated returns the call to gitinfo.Build() within the given file, as
+// generated by "gitinfo -X", and its map of git information, or nil if the
+// file does not contain such a call. The git information is either given
+// to Build() as a map literal, or declared in the file as an array literal
+// of field name and value pairs.
+func Generated(file *ast.File) (*ast.CallExpr, map[string]string) {
+	// how is go-gitinfo imported?
+	_name := ""
+	for _, _import := range file.Imports {
+		_path, _err := strconv.Unquote(_import.Path.Value)
+		if _err != nil || _path != _IMPORT {
+			continue
+		} else if _import.Name != nil {
+			_name = _import.Name.Name
+		} else {
+			_name = "gitinfo"
+		}
+	}
+	if _name == "" || _name == "_" || _name == "." {
+		return nil, nil
+	}
+
+	// find the call to Build(), and the map or array literal of the git
+	// information
+	var (
+		_call  *ast.CallExpr
+		_map   map[string]string
+		_pairs map[string]string
+	)
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch _node := n.(type) {
+		case *ast.CallExpr:
+			if _call != nil || len(_node.Args) != 1 {
+				return true
+			}
+			_selector, _ok := _node.Fun.(*ast.SelectorExpr)
+			if !_ok || _selector.Sel.Name != "Build" {
+				return true
+			} else if _package, _ok := _selector.X.(*ast.Ident); !_ok ||
+				_package.Name != _name {
+				return true
+			}
+			_call = _node
+
+			// do we have a map literal?
+			if _literal, _ok := _node.Args[0].(*ast.CompositeLit); _ok {
+				_map = entries(_literal)
+				return false
+			}
+		case *ast.CompositeLit:
+			if _pairs == nil {
+				_pairs = pairs(_node)
+			}
+		}
+
+		return true
+	})
+
+	// prefer the map literal given to Build()
+	if _call == nil {
+		return nil, nil
+	} else if _map == nil {
+		_map = _pairs
+	}
+	if _map == nil {
+		_map = make(map[string]string)
+	}
+
+	return _call, _map
+} // Generated()
+
+// entries returns the string keys and values of the given map literal
+func entries(literal *ast.CompositeLit) map[string]string {
+	_map := make(map[string]string)
+	for _, _element := range literal.Elts {
+		_entry, _ok := _element.(*ast.KeyValueExpr)
+		if !_ok {
+			continue
+		}
+		_key, _ok := unquote(_entry.Key)
+		if !_ok {
+			continue
+		}
+		_value, _ok := unquote(_entry.Value)
+		if !_ok {
+			continue
+		}
+		_map[_key] = _value
+	}
+
+	return _map
+} // entries()
+
+// pairs returns the map of the field name and value pairs of the given
+// array literal, or nil if the literal is not an array of string pairs
+func pairs(literal *ast.CompositeLit) map[string]string {
+	_array, _ok := literal.Type.(*ast.ArrayType)
+	if !_ok {
+		return nil
+	} else if _pair, _ok := _array.Elt.(*ast.ArrayType); !_ok ||
+		_pair.Len == nil {
+		return nil
+	}
+
+	_map := make(map[string]string)
+	for _, _element := range literal.Elts {
+		_pair, _ok := _element.(*ast.CompositeLit)
+		if !_ok || len(_pair.Elts) != 2 {
+			return nil
+		}
+		_key, _ok := unquote(_pair.Elts[0])
+		if !_ok {
+			return nil
+		}
+		_value, _ok := unquote(_pair.Elts[1])
+		if !_ok {
+			return nil
+		}
+		_map[_key] = _value
+	}
+
+	return _map
+} // pairs()
+
+// unquote returns the value of the given string literal expression, and
+// true, or false if the expression is not a string literal
+func unquote(e ast.Expr) (string, bool) {
+	_literal, _ok := e.(*ast.BasicLit)
+	if !_ok || _literal.Kind != token.STRING {
+		return "", false
+	}
+	_value, _err := strconv.Unquote(_literal.Value)
+
+	return _value, _err == nil
+} // unquote()
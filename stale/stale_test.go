@@ -0,0 +1,280 @@
+package stale_test
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/denormal/go-gitinfo"
+	"github.com/denormal/go-gitinfo/stale"
+	"github.com/denormal/go-gittools"
+	"golang.org/x/tools/go/analysis"
+)
+
+// the templates of the git information generated by this and earlier
+// versions of gitinfo
+var _GENERATED = []string{`// generated by gitinfo
+package main
+
+import "github.com/denormal/go-gitinfo"
+
+var git gitinfo.GitInfo
+
+var _git = [...][2]string{
+	{"branch", "main"},
+	{"commit", %q},
+	{"modified", "false"},
+	{"path", "/elsewhere"},
+}
+
+func init() {
+	if git == nil {
+		_map := make(map[string]string, len(_git))
+		for _, _field := range _git {
+			_map[_field[0]] = _field[1]
+		}
+		git = gitinfo.Build(_map)
+	}
+}
+`, `// generated by gitinfo
+package main
+
+import "github.com/denormal/go-gitinfo"
+
+var git gitinfo.GitInfo
+
+func init() {
+	git = gitinfo.Build(map[string]string{
+		"branch":   "main",
+		"commit":   %q,
+		"modified": "false",
+		"path":     "/elsewhere",
+	})
+}
+`,
+}
+
+func TestCheck(t *testing.T) {
+	// if we don't have git installed, then skip this test
+	if !gittools.HasGit() {
+		t.Skip("git not installed")
+	}
+
+	for _, _generated := range _GENERATED {
+		_dir := repository(t)
+		defer os.RemoveAll(_dir)
+		_commit := commit(t, _dir, "initial")
+
+		// files without generated git information cannot be checked
+		_file := filepath.Join(_dir, "git.go")
+		write(t, _file, "package main\n")
+		_, _err := stale.Check(_file, false)
+		if _err != stale.MissingBuildError {
+			t.Fatalf(
+				"expected %v from Check(); got %v",
+				stale.MissingBuildError, _err,
+			)
+		}
+
+		// generated files are not stale when first generated
+		//		- the generated file does not modify the working copy
+		write(t, _file, fmt.Sprintf(_generated, _commit))
+		check(t, _file, false)
+
+		// environment fields are only compared when requested
+		check(t, _file, true, stale.Difference{
+			gitinfo.PATH, "/elsewhere", _dir,
+		})
+
+		// new commits and modifications make the generated file stale
+		_new := commit(t, _dir, "second")
+		write(t, filepath.Join(_dir, "README"), "modified\n")
+		check(t, _file, false,
+			stale.Difference{"commit", _commit, _new},
+			stale.Difference{"modified", "false", "true"},
+		)
+	}
+} // TestCheck()
+
+func TestCount(t *testing.T) {
+	// if we don't have git installed, then skip this test
+	if !gittools.HasGit() {
+		t.Skip("git not installed")
+	}
+
+	// create a tagged commit, followed by a merged commit
+	_dir := repository(t)
+	defer os.RemoveAll(_dir)
+	commit(t, _dir, "initial")
+	git(t, _dir, "tag", "v1.0.0")
+	git(t, _dir, "checkout", "-q", "-b", "side")
+	commit(t, _dir, "side")
+	git(t, _dir, "checkout", "-q", "main")
+	git(t, _dir,
+		"-c", "user.name=gitinfo", "-c", "user.email=gitinfo@example.com",
+		"merge", "-q", "--no-ff", "-m", "merge", "side",
+	)
+	_info, _err := gitinfo.NewWithPath(_dir)
+	if _err != nil {
+		t.Fatalf("unexpected error from NewWithPath(): %s", _err.Error())
+	}
+
+	// counts generated with -first-parent and -since-tag should be compared
+	// with the live count for the same options
+	_file := filepath.Join(_dir, "git.go")
+	for _, _options := range []gitinfo.CountOptions{
+		{},
+		{FirstParent: true},
+		{SinceTag: true},
+		{FirstParent: true, SinceTag: true},
+	} {
+		_count, _err := _info.CommitCount(_options)
+		if _err != nil {
+			t.Fatalf("unexpected error from CommitCount(): %s", _err.Error())
+		}
+		_generated := func(count int) string {
+			return fmt.Sprintf(`// generated by gitinfo
+package main
+
+import "github.com/denormal/go-gitinfo"
+
+var git = gitinfo.Build(map[string]string{
+	"count":         "%d",
+	"count.options": %q,
+})
+`, count, _options.String())
+		}
+
+		write(t, _file, _generated(_count))
+		check(t, _file, false)
+		write(t, _file, _generated(_count+1))
+		check(t, _file, false, stale.Difference{
+			gitinfo.COUNT, fmt.Sprint(_count + 1), fmt.Sprint(_count),
+		})
+	}
+} // TestCount()
+
+func TestAnalyzer(t *testing.T) {
+	// if we don't have git installed, then skip this test
+	if !gittools.HasGit() {
+		t.Skip("git not installed")
+	}
+
+	_dir := repository(t)
+	defer os.RemoveAll(_dir)
+	_commit := commit(t, _dir, "initial")
+	_file := filepath.Join(_dir, "git.go")
+	write(t, _file, fmt.Sprintf(_GENERATED[0], _commit))
+
+	// is the stale file reported?
+	_analyze := func() []analysis.Diagnostic {
+		_fset := token.NewFileSet()
+		_ast, _err := parser.ParseFile(_fset, _file, nil, 0)
+		if _err != nil {
+			t.Fatalf("unable to parse %s: %s", _file, _err.Error())
+		}
+		_diagnostics := make([]analysis.Diagnostic, 0)
+		_pass := &analysis.Pass{
+			Analyzer: stale.Analyzer,
+			Fset:     _fset,
+			Files:    []*ast.File{_ast},
+			Report: func(d analysis.Diagnostic) {
+				_diagnostics = append(_diagnostics, d)
+			},
+		}
+		_, _err = stale.Analyzer.Run(_pass)
+		if _err != nil {
+			t.Fatalf("unexpected error from Run(): %s", _err.Error())
+		}
+		return _diagnostics
+	}
+	_diagnostics := _analyze()
+	if len(_diagnostics) != 0 {
+		t.Fatalf("unexpected diagnostics: %v", _diagnostics)
+	}
+
+	commit(t, _dir, "second")
+	_diagnostics = _analyze()
+	if len(_diagnostics) != 1 {
+		t.Fatalf("expected one diagnostic; got %v", _diagnostics)
+	} else if !strings.HasPrefix(
+		_diagnostics[0].Message, "stale git information: commit: ",
+	) {
+		t.Fatalf("unexpected diagnostic %q", _diagnostics[0].Message)
+	}
+} // TestAnalyzer()
+
+//
+// helper functions
+//
+
+func check(
+	t *testing.T,
+	file string,
+	all bool,
+	expected ...stale.Difference,
+) {
+	_diffs, _err := stale.Check(file, all)
+	if _err != nil {
+		t.Fatalf("unexpected error from Check(): %s", _err.Error())
+	} else if len(_diffs) != len(expected) {
+		t.Fatalf("unexpected differences; expected %v, got %v",
+			expected, _diffs,
+		)
+	}
+	for _i, _diff := range _diffs {
+		if _diff != expected[_i] {
+			t.Fatalf("unexpected difference; expected %v, got %v",
+				expected[_i], _diff,
+			)
+		}
+	}
+} // check()
+
+func repository(t *testing.T) string {
+	_tmp, _err := ioutil.TempDir("", "")
+	if _err != nil {
+		t.Fatalf("unable to create temporary directory: %s", _err.Error())
+	}
+	_dir, _err := filepath.EvalSymlinks(_tmp)
+	if _err != nil {
+		t.Fatalf("unable to resolve temporary directory: %s", _err.Error())
+	}
+	git(t, _dir, "init", "-q", "-b", "main")
+	write(t, filepath.Join(_dir, "README"), "readme\n")
+
+	return _dir
+} // repository()
+
+func commit(t *testing.T, dir, msg string) string {
+	git(t, dir, "add", "README")
+	git(t, dir,
+		"-c", "user.name=gitinfo", "-c", "user.email=gitinfo@example.com",
+		"-c", "commit.gpgsign=false",
+		"commit", "-q", "--allow-empty", "-m", msg,
+	)
+
+	return strings.TrimSpace(git(t, dir, "rev-parse", "HEAD"))
+} // commit()
+
+func write(t *testing.T, path, content string) {
+	_err := ioutil.WriteFile(path, []byte(content), 0644)
+	if _err != nil {
+		t.Fatalf("unable to write %s: %s", path, _err.Error())
+	}
+} // write()
+
+func git(t *testing.T, dir string, args ...string) string {
+	_output, _err := gittools.RunInPath(dir, args...)
+	if _err != nil {
+		t.Fatalf("git %s: %s", strings.Join(args, " "), _err.Error())
+	}
+
+	return string(_output)
+} // git()
@@ -0,0 +1,209 @@
+package gitinfo
+
+import (
+	"strings"
+)
+
+// the default trailer separators, as used by "git interpret-trailers"
+const _SEPARATORS = ":"
+
+// CO_AUTHORED_BY is the trailer token identifying commit co-authors.
+const CO_AUTHORED_BY = "Co-authored-by"
+
+var (
+	// the prefixes of trailers generated by git itself, which identify a
+	// trailer block, even if it contains other lines
+	_GENERATED = []string{"Signed-off-by: ", "(cherry picked from commit "}
+
+	// the "trailer.<token>.*" settings that configure a trailer token
+	_SETTINGS = map[string]bool{
+		"key":       true,
+		"command":   true,
+		"cmd":       true,
+		"where":     true,
+		"ifexists":  true,
+		"ifmissing": true,
+	}
+)
+
+// trailer represents a single commit message trailer
+type trailer struct {
+	token string
+	value string
+}
+
+// configured represents a trailer token configured by "trailer.<name>.*"
+// settings, with the key given by "trailer.<name>.key" (if any)
+type configured struct {
+	name string
+	key  string
+}
+
+// trailers returns the trailers of the given commit message, using the rules
+// of "git interpret-trailers": the trailers are the lines of the final
+// paragraph of the message (other than the title paragraph), provided
+// that either every line is a trailer, or at least 25% of the lines are
+// trailers and one of them is generated by git or uses one of the given
+// configured tokens. Lines beginning with whitespace continue the preceding
+// trailer, and are unfolded into its value. A trailer is a token of
+// alphanumeric characters and hyphens, followed by one of the given
+// separators. Tokens matching a configured token with a key are replaced by
+// that key.
+func trailers(message, separators string, tokens []configured) []trailer {
+	_lines := strings.Split(strings.TrimRight(message, " \t\n"), "\n")
+
+	// the first paragraph is the title and cannot contain trailers
+	_title := 0
+	for _title < len(_lines) && !blank(_lines[_title]) {
+		_title++
+	}
+
+	// working backwards from the end of the message, find the start of the
+	// trailer block
+	var (
+		_start        = -1
+		_recognised   = false
+		_trailers     = 0
+		_other        = 0
+		_continuation = 0
+	)
+	for _i := len(_lines) - 1; _i >= _title; _i-- {
+		_line := _lines[_i]
+		if blank(_line) {
+			_other += _continuation
+			if (_recognised && _trailers*3 >= _other) ||
+				(_trailers > 0 && _other == 0) {
+				_start = _i + 1
+			}
+			break
+		}
+
+		// is this a git-generated trailer?
+		_generated := false
+		for _, _prefix := range _GENERATED {
+			if strings.HasPrefix(_line, _prefix) {
+				_generated = true
+				break
+			}
+		}
+
+		_pos := separator(_line, separators)
+		switch {
+		case _generated:
+			_trailers++
+			_continuation = 0
+			_recognised = true
+		case _pos >= 1 && !space(_line[0]):
+			_trailers++
+			_continuation = 0
+			//		- as with git, the token is matched before it is
+			//		  trimmed of whitespace
+			if match(_line[:_pos], tokens) != nil {
+				_recognised = true
+			}
+		case space(_line[0]):
+			_continuation++
+		default:
+			_other++
+			_other += _continuation
+			_continuation = 0
+		}
+	}
+	if _start < 0 {
+		return nil
+	}
+
+	// extract the trailers from the trailer block
+	//		- continuation lines are appended to the preceding trailer
+	//		- lines that are not trailers are ignored
+	_rtn := make([]trailer, 0)
+	_last := -1
+	for _, _line := range _lines[_start:] {
+		if _last >= 0 && space(_line[0]) {
+			_rtn[_last].value += " " + strings.TrimSpace(_line)
+			continue
+		}
+
+		_pos := separator(_line, separators)
+		if _pos < 1 {
+			_last = -1
+			continue
+		}
+		_token := strings.TrimSpace(_line[:_pos])
+		if _match := match(_token, tokens); _match != nil && _match.key != "" {
+			_token = _match.key
+		}
+		_rtn = append(_rtn, trailer{
+			token: _token,
+			value: strings.TrimSpace(_line[_pos+1:]),
+		})
+		_last = len(_rtn) - 1
+	}
+
+	// unfolded values are trimmed of leading and trailing whitespace
+	for _i := range _rtn {
+		_rtn[_i].value = strings.TrimSpace(_rtn[_i].value)
+	}
+
+	return _rtn
+} // trailers()
+
+// separator returns the position of the trailer separator within the given
+// line, or -1 if the line does not start with a trailer token followed by
+// one of the given separators. As with git, the token may be followed by
+// whitespace before the separator.
+func separator(line, separators string) int {
+	_whitespace := false
+	for _i := 0; _i < len(line); _i++ {
+		_c := line[_i]
+		if strings.IndexByte(separators, _c) >= 0 {
+			return _i
+		}
+		if !_whitespace && (alphanumeric(_c) || _c == '-') {
+			continue
+		}
+		if _i != 0 && (_c == ' ' || _c == '\t') {
+			_whitespace = true
+			continue
+		}
+		break
+	}
+
+	return -1
+} // separator()
+
+// match returns the first of the given configured tokens matched by the
+// given trailer token, or nil if there is no match. As with git, the trailer
+// token matches if it is a case-insensitive prefix of the configured name or
+// key.
+func match(token string, tokens []configured) *configured {
+	if token == "" {
+		return nil
+	}
+	for _i, _configured := range tokens {
+		for _, _t := range []string{_configured.name, _configured.key} {
+			if len(token) <= len(_t) &&
+				strings.EqualFold(token, _t[:len(token)]) {
+				return &tokens[_i]
+			}
+		}
+	}
+
+	return nil
+} // match()
+
+// blank returns true if the line contains only whitespace
+func blank(line string) bool {
+	return strings.TrimSpace(line) == ""
+} // blank()
+
+// space returns true if c is an ASCII whitespace character
+func space(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' ||
+		c == '\v' || c == '\f'
+} // space()
+
+// alphanumeric returns true if c is an ASCII letter or digit
+func alphanumeric(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+} // alphanumeric()
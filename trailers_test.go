@@ -0,0 +1,237 @@
+package gitinfo_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/denormal/go-gitinfo"
+	"github.com/denormal/go-gittools"
+)
+
+// _MESSAGES are the commit messages for testing trailer extraction
+var _MESSAGES = []string{
+	"subject only",
+	"Reviewed-by: the subject is never a trailer",
+	"subject\n\nSigned-off-by: Jane Doe <jane@example.com>",
+	"subject\n\nbody\n\nReviewed-by: Jane Doe <jane@example.com>\n" +
+		"Co-authored-by: John Smith <john@example.com>\n" +
+		"Co-authored-by: Joe Bloggs <joe@example.com>",
+	// folded values are unfolded
+	"subject\n\nTicket: ABC-123\n  and ABC-456\n\tand ABC-789\nAcked-by: Jane",
+	// whitespace is allowed between the token and the separator
+	"subject\n\nKey : value\nOther-Key\t: other value",
+	// a block with other lines requires a git-generated trailer
+	"subject\n\nnot a trailer\nReviewed-by: Jane",
+	"subject\n\nnot a trailer\nSigned-off-by: Jane\nReviewed-by: John",
+	"subject\n\none\ntwo\nthree\nfour\nSigned-off-by: Jane",
+	"subject\n\n(cherry picked from commit 0123456)\nnot a trailer\n" +
+		"Reviewed-by: John",
+	// only the final paragraph may contain trailers
+	"subject\n\nReviewed-by: Jane\n\nthis is the body",
+	// custom separators
+	"subject\n\nCloses #12\nFixes #34\nRefs: 56",
+	// tokens may only contain alphanumerics and hyphens
+	"subject\n\nNot_A-Token: value\nSigned-off-by: Jane",
+	"subject\n\nBREAKING CHANGE: not a trailer\nSigned-off-by: Jane",
+	// configured tokens identify a trailer block, and use their key
+	"subject\n\nnot a trailer\nreviewed-by: Jane",
+	"subject\n\nnot a trailer\nRev: Jane",
+	"subject\n\nnot a trailer\nticket: ABC-123",
+	"subject\n\nsigned-off-by: Jane\nREVIEWED-BY: John\nticket: ABC-123",
+}
+
+func TestTrailers(t *testing.T) {
+	// if we don't have git installed, then skip this test
+	if !gittools.HasGit() {
+		t.Skip("git not installed")
+	}
+
+	_dir := repository(t)
+	defer os.RemoveAll(_dir)
+
+	// test with the default and custom separators, and configured tokens
+	_configs := [][]string{
+		nil,
+		{"trailer.separators", ":#"},
+		{
+			"trailer.sign.key", "Signed-off-by",
+			"trailer.reviewed.key", "Reviewed-by",
+			"trailer.ticket.where", "end",
+		},
+	}
+	for _, _config := range _configs {
+		for _i := 0; _i < len(_config); _i += 2 {
+			git(t, _dir, "config", _config[_i], _config[_i+1])
+		}
+
+		for _, _message := range _MESSAGES {
+			commit(t, _dir, "2024-01-01T00:00:00Z", _message)
+
+			_info, _err := gitinfo.NewWithPath(_dir)
+			if _err != nil {
+				t.Fatalf("unexpected error from NewWithPath(): %s", _err.Error())
+			}
+			_commit, _err := _info.Commit()
+			if _err != nil {
+				t.Fatalf("unexpected error from Commit(): %s", _err.Error())
+			}
+
+			// ensure the message is as committed
+			_got, _err := _commit.Message()
+			if _err != nil {
+				t.Fatalf("unexpected error from Message(): %s", _err.Error())
+			} else if _got != _message {
+				t.Fatalf(
+					"unexpected message; expected %q, got %q",
+					_message, _got,
+				)
+			}
+
+			// the trailers should match those reported by git
+			_expected := interpret(t, _dir, _message)
+			_trailers := make([]string, 0)
+			for _token, _values := range _commit.Trailers() {
+				for _, _value := range _values {
+					_trailers = append(_trailers, _token+": "+_value)
+				}
+			}
+			sort.Strings(_trailers)
+			if strings.Join(_trailers, "\n") != strings.Join(_expected, "\n") {
+				t.Fatalf(
+					"%q (config %q): unexpected trailers; "+
+						"expected %q, got %q",
+					_message, _config, _expected, _trailers,
+				)
+			}
+		}
+	}
+
+	// ensure the trailers of a known message are as expected
+	_info, _err := gitinfo.NewWithPath(_dir)
+	if _err != nil {
+		t.Fatalf("unexpected error from NewWithPath(): %s", _err.Error())
+	}
+	_commits, _err := _info.Commits("", "")
+	if _err != nil {
+		t.Fatalf("unexpected error from Commits(): %s", _err.Error())
+	} else if len(_commits) != len(_configs)*len(_MESSAGES) {
+		t.Fatalf(
+			"unexpected commits; expected %d, got %d",
+			len(_configs)*len(_MESSAGES), len(_commits),
+		)
+	}
+	_trailers := _commits[len(_MESSAGES)-5].Trailers()
+	for _token, _value := range map[string]string{
+		"Ticket":   "ABC-123 and ABC-456 and ABC-789",
+		"Acked-by": "Jane",
+	} {
+		if len(_trailers[_token]) != 1 || _trailers[_token][0] != _value {
+			t.Fatalf(
+				"unexpected %q trailer; expected [%q], got %q",
+				_token, _value, _trailers[_token],
+			)
+		}
+	}
+} // TestTrailers()
+
+func TestCoAuthors(t *testing.T) {
+	// if we don't have git installed, then skip this test
+	if !gittools.HasGit() {
+		t.Skip("git not installed")
+	}
+
+	_dir := repository(t)
+	defer os.RemoveAll(_dir)
+
+	commit(t, _dir, "2024-01-01T00:00:00Z", "subject\n\n"+
+		"Co-authored-by: John Smith <john@example.com>\n"+
+		"co-authored-by: Joe Bloggs <joe@example.com>\n"+
+		"Co-authored-by: nobody\n"+
+		"Signed-off-by: Jane Doe <jane@example.com>",
+	)
+	commit(t, _dir, "2024-01-01T00:00:00Z", "subject without trailers")
+
+	// the co-authors should not be influenced by the environment
+	defer unset(t, "GIT_AUTHOR_NAME")
+	set(t, "GIT_AUTHOR_NAME", "Not A Co-author")
+
+	_info, _err := gitinfo.NewWithPath(_dir)
+	if _err != nil {
+		t.Fatalf("unexpected error from NewWithPath(): %s", _err.Error())
+	}
+	_commits, _err := _info.Commits("HEAD~1", "")
+	if _err != nil {
+		t.Fatalf("unexpected error from Commits(): %s", _err.Error())
+	} else if len(_commits) != 1 {
+		t.Fatalf("unexpected commits; expected %d, got %d", 1, len(_commits))
+	} else if _users := _commits[0].CoAuthors(); len(_users) != 0 {
+		t.Fatalf("unexpected co-authors: %v", _users)
+	}
+
+	_commits, _err = _info.Commits("", "HEAD~1")
+	if _err != nil {
+		t.Fatalf("unexpected error from Commits(): %s", _err.Error())
+	} else if len(_commits) != 1 {
+		t.Fatalf("unexpected commits; expected %d, got %d", 1, len(_commits))
+	}
+	_users := _commits[0].CoAuthors()
+	_expected := [][3]string{
+		{"John Smith", "john@example.com", "John Smith <john@example.com>"},
+		{"Joe Bloggs", "joe@example.com", "Joe Bloggs <joe@example.com>"},
+		{"nobody", "", "nobody"},
+	}
+	if len(_users) != len(_expected) {
+		t.Fatalf(
+			"unexpected co-authors; expected %d, got %d",
+			len(_expected), len(_users),
+		)
+	}
+	for _i, _user := range _users {
+		if _user.Name() != _expected[_i][0] ||
+			_user.Email() != _expected[_i][1] ||
+			_user.String() != _expected[_i][2] {
+			t.Fatalf(
+				"unexpected co-author; expected %q, got %q",
+				_expected[_i][2], _user.String(),
+			)
+		}
+	}
+
+	// commits from Build() have no trailers
+	_commit, _ := gitinfo.Build(nil).Commit()
+	if len(_commit.Trailers()) != 0 || len(_commit.CoAuthors()) != 0 {
+		t.Fatal("unexpected trailers for Build() commit")
+	}
+} // TestCoAuthors()
+
+//
+// helper functions
+//
+
+// interpret returns the trailers of the given message as reported by
+// "git interpret-trailers", sorted for comparison
+func interpret(t *testing.T, dir, message string) []string {
+	_file, _err := ioutil.TempFile("", "")
+	if _err != nil {
+		t.Fatalf("unable to create temporary file: %s", _err.Error())
+	}
+	defer os.Remove(_file.Name())
+	_file.WriteString(message + "\n")
+	_file.Close()
+
+	_path, _ := filepath.Abs(_file.Name())
+	_output := git(t, dir, "interpret-trailers", "--parse", _path)
+	_trailers := make([]string, 0)
+	for _, _line := range strings.Split(_output, "\n") {
+		if _line != "" {
+			_trailers = append(_trailers, _line)
+		}
+	}
+	sort.Strings(_trailers)
+
+	return _trailers
+} // interpret()
@@ -0,0 +1,47 @@
+package gitinfo_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/denormal/go-gitinfo"
+	"github.com/denormal/go-gittools"
+)
+
+func TestCommitURL(t *testing.T) {
+	// if we don't have git installed, then skip this test
+	if !gittools.HasGit() {
+		t.Skip("git not installed")
+	}
+
+	_dir := repository(t)
+	defer os.RemoveAll(_dir)
+	_hash := commit(t, _dir, "2024-01-01T00:00:00Z", "initial")
+
+	// without a remote, there is no commit URL
+	url(t, _dir, "")
+
+	// with a known hosting provider, the commit URL is reported
+	git(t, _dir, "remote", "add", "origin", "git@gitlab.com:group/repo.git")
+	url(t, _dir, "https://gitlab.com/group/repo/-/commit/"+_hash)
+} // TestCommitURL()
+
+//
+// helper functions
+//
+
+func url(t *testing.T, dir, expected string) {
+	_info, _err := gitinfo.NewWithPath(dir)
+	if _err != nil {
+		t.Fatalf("unexpected error from NewWithPath(): %s", _err.Error())
+	}
+	_url, _ok := _info.Map()[gitinfo.URL_COMMIT]
+	if !_ok {
+		t.Fatalf("expected map value for %q, none found", gitinfo.URL_COMMIT)
+	} else if _url != expected {
+		t.Fatalf(
+			"unexpected %s; expected %q, got %q",
+			gitinfo.URL_COMMIT, expected, _url,
+		)
+	}
+} // url()
@@ -2,7 +2,9 @@ package gitinfo
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
+	"strings"
 
 	"github.com/denormal/go-gitconfig"
 )
@@ -20,6 +22,11 @@ type User interface {
 	// String() returns a string representation of the git user's name and
 	// e-mail address, or the empty string if neither are defined.
 	String() string
+
+	// LogValue returns the name and e-mail address of the git user as a
+	// slog group value, so that a User may be logged directly with
+	// log/slog. Undefined values are omitted.
+	LogValue() slog.Value
 }
 
 // user is the implementation of the User interface
@@ -49,6 +56,21 @@ func newUser(config gitconfig.GitConfig) User {
 	return &user{name: _name, email: _email}
 } // newUser()
 
+// parseUser returns the User described by the string s, of the form
+// "name <email>", as used by commit trailers such as "Co-authored-by". Unlike
+// the git user for a working copy, the name and e-mail address of the
+// returned User are not influenced by the environment.
+func parseUser(s string) User {
+	_name, _email := strings.TrimSpace(s), ""
+	_open := strings.LastIndex(_name, "<")
+	if _open >= 0 && strings.HasSuffix(_name, ">") {
+		_email = strings.TrimSpace(_name[_open+1 : len(_name)-1])
+		_name = strings.TrimSpace(_name[:_open])
+	}
+
+	return &person{name: _name, email: _email}
+} // parseUser()
+
 // Name returns the name of the current git user, or the empty string
 // if no name is configured.
 func (u *user) Name() string {
@@ -85,14 +107,34 @@ func (u *user) Email() string {
 // String() returns a string representation of the git user's name and
 // e-mail address, or the empty string if neither are defined.
 func (u *user) String() string {
-	_name := u.Name()
-	_email := u.Email()
+	return format(u.Name(), u.Email())
+} // String()
 
-	if _name == "" {
-		return _email
-	} else if _email == "" {
-		return _name
+// person is the implementation of the User interface for users named within
+// the git history, such as commit co-authors
+type person struct {
+	name  string
+	email string
+}
+
+func (p *person) Name() string   { return p.name }
+func (p *person) Email() string  { return p.email }
+func (p *person) String() string { return format(p.name, p.email) }
+
+// format returns the string representation of a user's name and e-mail
+// address, or the empty string if neither are defined.
+func format(name, email string) string {
+	if name == "" {
+		return email
+	} else if email == "" {
+		return name
 	} else {
-		return fmt.Sprintf("%s <%s>", _name, _email)
+		return fmt.Sprintf("%s <%s>", name, email)
 	}
-} // String()
+} // format()
+
+// ensure user and person implement the User interface
+var (
+	_ User = &user{}
+	_ User = &person{}
+)